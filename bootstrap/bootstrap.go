@@ -29,6 +29,7 @@ func main() {
 		"none",
 		*message,
 		[]byte(*config),
+		nil,
 	)
 	if err != nil {
 		fmt.Println("Failed to bootstrap config: ", err)
@@ -1,7 +1,10 @@
 package dao
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/HailoOSS/config-service/domain"
@@ -23,11 +26,52 @@ const (
 	CfAuditService = "auditService"
 	// CfAuditServiceIndex is where we keep an index of which rows exist in our time series
 	CfAuditServiceIndex = "auditServiceIndex"
+	// CfRevisions is CF where we store every revision of each config, one row per ID
+	// with a column per revision
+	CfRevisions = "revisions"
+	// CfIds is CF where we index which IDs exist, as a single row with a column per ID
+	// holding a summary of its latest change
+	CfIds = "ids"
+	// idsRow is the key of the row in CfIds
+	idsRow = "ids"
+	// CfProposals is CF where we store proposed changes, as a single row with a column
+	// per proposal, along with an index of those pending
+	CfProposals = "proposals"
+	// proposalsRow is the key of the row of every proposal in CfProposals
+	proposalsRow = "proposals"
+	// CfScheduled is CF where we store scheduled changes, as a single row with a column
+	// per scheduled change, along with an index of those pending
+	CfScheduled = "scheduled"
+	// scheduledRow is the key of the row of every scheduled change in CfScheduled
+	scheduledRow = "scheduled"
+	// CfOverrides is CF where we store temporary overrides, as a single row with a column
+	// per override, along with an index of those active
+	CfOverrides = "overrides"
+	// overridesRow is the key of the row of every override in CfOverrides
+	overridesRow = "overrides"
+
+	// revisionPageSize is how many revisions we read at a time when searching by time
+	revisionPageSize = 100
+	// proposalPageSize is how many proposals we read at a time when listing them
+	proposalPageSize = 100
+	// scheduledPageSize is how many scheduled changes we read at a time when listing them
+	scheduledPageSize = 100
+	// overridePageSize is how many overrides we read at a time when listing them
+	overridePageSize = 100
+	// indexPageSize is how many configs we read at a time when indexing IDs
+	indexPageSize = 100
+
+	// openRow is the key of the row indexing which records are still open, ie: pending
+	// proposals and scheduled changes, and active overrides, in the CFs which keep them
+	openRow = "open"
+	// closedTtl is how long closed records are kept for, in seconds, so that the rows of
+	// records do not grow forever
+	closedTtl = 90 * 24 * 60 * 60
 )
 
 var (
 	// Cfs is a list of all active CFs, which we should monitor
-	Cfs = []string{CfConfig, CfAudit, CfAuditIndex, CfAuditService, CfAuditServiceIndex}
+	Cfs = []string{CfConfig, CfAudit, CfAuditIndex, CfAuditService, CfAuditServiceIndex, CfRevisions, CfIds, CfProposals, CfScheduled, CfOverrides}
 
 	mapping         gossie.Mapping
 	changeTs        *timeseries.TimeSeries
@@ -113,19 +157,240 @@ func (r CassandraRepository) ReadConfig(ids []string) ([]*domain.ChangeSet, erro
 	return sortedResults, nil
 }
 
-// UpdateConfig writes out a changeset
+// revisionColumn is the column name for a revision, padded so that columns sort by revision
+func revisionColumn(revision int64) []byte {
+	return []byte(fmt.Sprintf("%020d", revision))
+}
+
+func unmarshalRevision(col *gossie.Column) (*domain.ChangeSet, error) {
+	cs := &domain.ChangeSet{}
+	if err := json.Unmarshal(col.Value, cs); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal revision %s: %v", col.Name, err)
+	}
+	return cs, nil
+}
+
+// ReadConfigAtRevision fetches a single revision of a config
+func (r CassandraRepository) ReadConfigAtRevision(id string, revision int64) (*domain.ChangeSet, error) {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	row, err := pool.Reader().Cf(CfRevisions).Columns([][]byte{revisionColumn(revision)}).Get([]byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get revision %v of %v: %v", revision, id, err)
+	}
+	if row == nil || len(row.Columns) == 0 {
+		return r.liveRevision(id, func(cs *domain.ChangeSet) bool {
+			return cs.Revision == revision
+		})
+	}
+
+	return unmarshalRevision(row.Columns[0])
+}
+
+// liveRevision returns the config row for id in place of a revision missing from the
+// revisions CF, if it matches. Configs last written before revisions were kept have a
+// config row but no revisions row until they are next changed.
+func (r CassandraRepository) liveRevision(id string, match func(cs *domain.ChangeSet) bool) (*domain.ChangeSet, error) {
+	live, err := r.ReadConfig([]string{id})
+	if err != nil {
+		return nil, err
+	}
+	return matchLive(live, match)
+}
+
+func matchLive(live []*domain.ChangeSet, match func(cs *domain.ChangeSet) bool) (*domain.ChangeSet, error) {
+	if len(live) == 0 || !match(live[0]) {
+		return nil, domain.ErrRevisionNotFound
+	}
+	return live[0], nil
+}
+
+// unrecorded returns those of the config rows live which have no revision recorded, as
+// they were last written before revisions were kept, so that they can be recorded before
+// being overwritten
+func unrecorded(live []*domain.ChangeSet) []*domain.ChangeSet {
+	var css []*domain.ChangeSet
+	for _, cs := range live {
+		if cs.Revision == 0 {
+			css = append(css, cs)
+		}
+	}
+	return css
+}
+
+// ReadConfigAt fetches the revision of a config which was current at time t, by
+// walking backwards from the latest revision, and then falling back to the config row
+func (r CassandraRepository) ReadConfigAt(id string, t time.Time) (*domain.ChangeSet, error) {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	var start []byte
+	for {
+		row, err := pool.Reader().Cf(CfRevisions).Slice(&gossie.Slice{
+			Start:    start,
+			Count:    revisionPageSize,
+			Reversed: true,
+		}).Get([]byte(id))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get revisions of %v: %v", id, err)
+		}
+		if row == nil {
+			break
+		}
+
+		for _, col := range row.Columns {
+			// Slices are inclusive, so the first column of later pages has been seen
+			if bytes.Equal(col.Name, start) {
+				continue
+			}
+			cs, err := unmarshalRevision(col)
+			if err != nil {
+				return nil, err
+			}
+			if !cs.Timestamp.After(t) {
+				return cs, nil
+			}
+		}
+
+		if len(row.Columns) < revisionPageSize {
+			break
+		}
+		start = row.Columns[len(row.Columns)-1].Name
+	}
+
+	return r.liveRevision(id, func(cs *domain.ChangeSet) bool {
+		return !cs.Timestamp.After(t)
+	})
+}
+
+// UpdateConfig writes out a changeset, along with a revision which is never overwritten
 func (r *CassandraRepository) UpdateConfig(cs *domain.ChangeSet) error {
+	return r.UpdateConfigs([]*domain.ChangeSet{cs})
+}
+
+// UpdateConfigs writes out several changesets in a single batch
+func (r *CassandraRepository) UpdateConfigs(css []*domain.ChangeSet) error {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	latest := make(map[string]*domain.ChangeSet, len(css))
+	for _, cs := range css {
+		latest[cs.Id] = cs
+	}
+
+	writer := pool.Writer()
+	if err := r.backfillRevisions(writer, css); err != nil {
+		return err
+	}
+	for _, cs := range css {
+		if latest[cs.Id] == cs {
+			row, err := mapping.Map(cs)
+			if err != nil {
+				return fmt.Errorf("Failed to map changeset: %v", err)
+			}
+			writer.Insert(CfConfig, row)
+
+			summary, err := json.Marshal(cs.Summary())
+			if err != nil {
+				return fmt.Errorf("Failed to marshal summary: %v", err)
+			}
+			writer.Insert(CfIds, &gossie.Row{
+				Key: []byte(idsRow),
+				Columns: []*gossie.Column{{
+					Name:  []byte(cs.Id),
+					Value: summary,
+				}},
+			})
+		}
+		revision, err := json.Marshal(cs)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal revision: %v", err)
+		}
+		writer.Insert(CfRevisions, &gossie.Row{
+			Key: []byte(cs.Id),
+			Columns: []*gossie.Column{{
+				Name:  revisionColumn(cs.Revision),
+				Value: revision,
+			}},
+		})
+		changeTs.Map(writer, cs, nil)
+		serviceChangeTs.Map(writer, cs, nil)
+	}
+
+	if err := writer.Run(); err != nil {
+		return fmt.Errorf("Error writing to C*: %v", err)
+	}
+
+	return nil
+}
+
+// backfillRevisions records the config rows about to be overwritten by css as revisions,
+// if they were last written before revisions were kept, so that they can still be read
+// and rolled back to
+func (r *CassandraRepository) backfillRevisions(writer gossie.Writer, css []*domain.ChangeSet) error {
+	var ids []string
+	for _, cs := range css {
+		// Only the first change to an ID since revisions were kept is revision 1
+		if cs.Revision == 1 {
+			ids = append(ids, cs.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	live, err := r.ReadConfig(ids)
+	if err != nil {
+		return err
+	}
+	for _, cs := range unrecorded(live) {
+		revision, err := json.Marshal(cs)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal revision: %v", err)
+		}
+		writer.Insert(CfRevisions, &gossie.Row{
+			Key: []byte(cs.Id),
+			Columns: []*gossie.Column{{
+				Name:  revisionColumn(cs.Revision),
+				Value: revision,
+			}},
+		})
+	}
+	return nil
+}
+
+// DeleteId removes an ID from the config CF and the index of IDs, keeping its revisions
+func (r *CassandraRepository) DeleteId(cs *domain.ChangeSet) error {
 	pool, err := cassandra.ConnectionPool(Keyspace)
 	if err != nil {
 		return fmt.Errorf("Failed to get connection pool: %v", err)
 	}
 
-	row, err := mapping.Map(cs)
+	revision, err := json.Marshal(cs)
 	if err != nil {
-		return fmt.Errorf("Failed to map changeset: %v", err)
+		return fmt.Errorf("Failed to marshal revision: %v", err)
 	}
+
 	writer := pool.Writer()
-	writer.Insert(CfConfig, row)
+	if err := r.backfillRevisions(writer, []*domain.ChangeSet{cs}); err != nil {
+		return err
+	}
+	writer.Delete(CfConfig, []byte(cs.Id))
+	writer.DeleteColumns(CfIds, []byte(idsRow), [][]byte{[]byte(cs.Id)})
+	writer.Insert(CfRevisions, &gossie.Row{
+		Key: []byte(cs.Id),
+		Columns: []*gossie.Column{{
+			Name:  revisionColumn(cs.Revision),
+			Value: revision,
+		}},
+	})
 	changeTs.Map(writer, cs, nil)
 	serviceChangeTs.Map(writer, cs, nil)
 
@@ -136,6 +401,389 @@ func (r *CassandraRepository) UpdateConfig(cs *domain.ChangeSet) error {
 	return nil
 }
 
+// ListIds returns a page of IDs from the index, starting with prefix and after cursor
+func (r *CassandraRepository) ListIds(prefix, cursor string, count int) ([]*domain.ChangeSet, string, error) {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	start := prefix
+	if cursor > start {
+		start = cursor
+	}
+	// Read one extra, as slices are inclusive of the cursor, and to see if there are more
+	row, err := pool.Reader().Cf(CfIds).Slice(&gossie.Slice{
+		Start: []byte(start),
+		Count: count + 2,
+	}).Get([]byte(idsRow))
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to list IDs: %v", err)
+	}
+	if row == nil {
+		return []*domain.ChangeSet{}, "", nil
+	}
+
+	css := make([]*domain.ChangeSet, 0, count)
+	for _, col := range row.Columns {
+		id := string(col.Name)
+		if id == cursor {
+			continue
+		}
+		if !strings.HasPrefix(id, prefix) {
+			break
+		}
+		if len(css) == count {
+			return css, css[count-1].Id, nil
+		}
+		cs := &domain.ChangeSet{}
+		if err := json.Unmarshal(col.Value, cs); err != nil {
+			return nil, "", fmt.Errorf("Failed to unmarshal summary of %s: %v", id, err)
+		}
+		css = append(css, cs)
+	}
+
+	return css, "", nil
+}
+
+// IndexIds adds every ID in the config CF which is missing from the index of IDs, as it
+// has not changed since the index was kept, returning how many were added. It walks
+// every row, so is run once by hand rather than by the service.
+func (r *CassandraRepository) IndexIds() (int, error) {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	indexed := 0
+	var start []byte
+	for {
+		rows, err := pool.Reader().Cf(CfConfig).Slice(&gossie.Slice{Count: 1}).RangeGet(&gossie.Range{
+			Start: start,
+			Count: indexPageSize,
+		})
+		if err != nil {
+			return indexed, fmt.Errorf("Failed to read configs: %v", err)
+		}
+
+		var ids []string
+		for _, row := range rows {
+			// Ranges are inclusive, so the first row of later pages has been seen, while
+			// deleted rows are left without columns
+			if bytes.Equal(row.Key, start) || len(row.Columns) == 0 {
+				continue
+			}
+			ids = append(ids, string(row.Key))
+		}
+		n, err := r.indexMissing(pool, ids)
+		indexed += n
+		if err != nil {
+			return indexed, err
+		}
+
+		if len(rows) < indexPageSize {
+			return indexed, nil
+		}
+		start = rows[len(rows)-1].Key
+	}
+}
+
+// indexMissing adds those of ids which are not yet in the index of IDs
+func (r *CassandraRepository) indexMissing(pool gossie.ConnectionPool, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	names := make([][]byte, len(ids))
+	for i, id := range ids {
+		names[i] = []byte(id)
+	}
+	row, err := pool.Reader().Cf(CfIds).Columns(names).Get([]byte(idsRow))
+	if err != nil {
+		return 0, fmt.Errorf("Failed to read index of IDs: %v", err)
+	}
+	exists := make(map[string]bool)
+	if row != nil {
+		for _, col := range row.Columns {
+			exists[string(col.Name)] = true
+		}
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if !exists[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+	css, err := r.ReadConfig(missing)
+	if err != nil {
+		return 0, err
+	}
+
+	writer := pool.Writer()
+	for _, cs := range css {
+		summary, err := json.Marshal(cs.Summary())
+		if err != nil {
+			return 0, fmt.Errorf("Failed to marshal summary: %v", err)
+		}
+		writer.Insert(CfIds, &gossie.Row{
+			Key: []byte(idsRow),
+			Columns: []*gossie.Column{{
+				Name:  []byte(cs.Id),
+				Value: summary,
+			}},
+		})
+	}
+	if err := writer.Run(); err != nil {
+		return 0, fmt.Errorf("Error writing to C*: %v", err)
+	}
+	return len(css), nil
+}
+
+// SaveProposal writes out a proposal, replacing any earlier version of it
+func (r *CassandraRepository) SaveProposal(p *domain.Proposal) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal proposal: %v", err)
+	}
+	return saveRecord(CfProposals, proposalsRow, p.ProposalId, b, p.Status == domain.ProposalPending)
+}
+
+// ReadProposal fetches a single proposal
+func (r *CassandraRepository) ReadProposal(proposalId string) (*domain.Proposal, error) {
+	b, err := readRecord(CfProposals, proposalsRow, proposalId)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, domain.ErrProposalNotFound
+	}
+
+	p := &domain.Proposal{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal proposal %v: %v", proposalId, err)
+	}
+	return p, nil
+}
+
+// ListProposals pages through every proposal, or only those pending, returning those
+// to change id, or all of them if id is empty
+func (r *CassandraRepository) ListProposals(id string, pending bool) ([]*domain.Proposal, error) {
+	proposals := make([]*domain.Proposal, 0)
+	err := listRecords(CfProposals, proposalsRow, pending, proposalPageSize, func(name, value []byte) error {
+		p := &domain.Proposal{}
+		if err := json.Unmarshal(value, p); err != nil {
+			return fmt.Errorf("Failed to unmarshal proposal %s: %v", name, err)
+		}
+		if id == "" || p.Change.Id == id {
+			proposals = append(proposals, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+// saveRecord writes value as the record name in the row of every record in cf. Open
+// records are added to the index of those open, while closed ones are removed from it
+// and expire after closedTtl.
+func saveRecord(cf, row, name string, value []byte, open bool) error {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	writer := pool.Writer()
+	if open {
+		writer.Insert(cf, recordRow(row, name, value))
+		writer.Insert(cf, openIndexRow(name))
+	} else {
+		writer.InsertTtl(cf, recordRow(row, name, value), closedTtl)
+		writer.DeleteColumns(cf, []byte(openRow), [][]byte{[]byte(name)})
+	}
+	if err := writer.Run(); err != nil {
+		return fmt.Errorf("Error writing to C*: %v", err)
+	}
+
+	return nil
+}
+
+func recordRow(row, name string, value []byte) *gossie.Row {
+	return &gossie.Row{
+		Key: []byte(row),
+		Columns: []*gossie.Column{{
+			Name:  []byte(name),
+			Value: value,
+		}},
+	}
+}
+
+// openIndexRow adds name to the index of open records, which only holds names so that
+// it cannot disagree with the records themselves
+func openIndexRow(name string) *gossie.Row {
+	return &gossie.Row{
+		Key: []byte(openRow),
+		Columns: []*gossie.Column{{
+			Name:  []byte(name),
+			Value: []byte{},
+		}},
+	}
+}
+
+// readRecord fetches the value of the record name in cf, or nil if there is none
+func readRecord(cf, row, name string) ([]byte, error) {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	r, err := pool.Reader().Cf(cf).Columns([][]byte{[]byte(name)}).Get([]byte(row))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get %v from %v: %v", name, cf, err)
+	}
+	if r == nil || len(r.Columns) == 0 {
+		return nil, nil
+	}
+	return r.Columns[0].Value, nil
+}
+
+// listRecords pages through the records in cf, or only those in the index of open
+// records if open is set, calling f with each
+func listRecords(cf, row string, open bool, pageSize int, f func(name, value []byte) error) error {
+	pool, err := cassandra.ConnectionPool(Keyspace)
+	if err != nil {
+		return fmt.Errorf("Failed to get connection pool: %v", err)
+	}
+
+	page := []byte(row)
+	if open {
+		page = []byte(openRow)
+	}
+	var start []byte
+	for {
+		r, err := pool.Reader().Cf(cf).Slice(&gossie.Slice{
+			Start: start,
+			Count: pageSize,
+		}).Get(page)
+		if err != nil {
+			return fmt.Errorf("Failed to list %v: %v", cf, err)
+		}
+		if r == nil {
+			return nil
+		}
+
+		// Slices are inclusive, so the first column of later pages has been seen
+		columns := r.Columns
+		if len(columns) > 0 && bytes.Equal(columns[0].Name, start) {
+			columns = columns[1:]
+		}
+		if open && len(columns) > 0 {
+			names := make([][]byte, len(columns))
+			for i, col := range columns {
+				names[i] = col.Name
+			}
+			records, err := pool.Reader().Cf(cf).Columns(names).Get([]byte(row))
+			if err != nil {
+				return fmt.Errorf("Failed to get open %v: %v", cf, err)
+			}
+			columns = nil
+			if records != nil {
+				columns = records.Columns
+			}
+		}
+		for _, col := range columns {
+			if err := f(col.Name, col.Value); err != nil {
+				return err
+			}
+		}
+
+		if len(r.Columns) < pageSize {
+			return nil
+		}
+		start = r.Columns[len(r.Columns)-1].Name
+	}
+}
+
+// SaveScheduledChange writes out a scheduled change, replacing any earlier version of it
+func (r *CassandraRepository) SaveScheduledChange(sc *domain.ScheduledChange) error {
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal scheduled change: %v", err)
+	}
+	return saveRecord(CfScheduled, scheduledRow, sc.ScheduleId, b, sc.Status == domain.SchedulePending)
+}
+
+// ReadScheduledChange fetches a single scheduled change
+func (r *CassandraRepository) ReadScheduledChange(scheduleId string) (*domain.ScheduledChange, error) {
+	b, err := readRecord(CfScheduled, scheduledRow, scheduleId)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, domain.ErrScheduleNotFound
+	}
+
+	sc := &domain.ScheduledChange{}
+	if err := json.Unmarshal(b, sc); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal scheduled change %v: %v", scheduleId, err)
+	}
+	return sc, nil
+}
+
+// ListScheduledChanges pages through every scheduled change, or only those pending,
+// returning those for id, or all of them if id is empty
+func (r *CassandraRepository) ListScheduledChanges(id string, pending bool) ([]*domain.ScheduledChange, error) {
+	scs := make([]*domain.ScheduledChange, 0)
+	err := listRecords(CfScheduled, scheduledRow, pending, scheduledPageSize, func(name, value []byte) error {
+		sc := &domain.ScheduledChange{}
+		if err := json.Unmarshal(value, sc); err != nil {
+			return fmt.Errorf("Failed to unmarshal scheduled change %s: %v", name, err)
+		}
+		if id == "" || sc.Id == id {
+			scs = append(scs, sc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scs, nil
+}
+
+// SaveOverride writes out a temporary override, replacing any earlier version of it
+func (r *CassandraRepository) SaveOverride(o *domain.Override) error {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal override: %v", err)
+	}
+	return saveRecord(CfOverrides, overridesRow, o.OverrideId, b, o.Status == domain.OverrideActive)
+}
+
+// ListOverrides pages through every override, or only those active, returning those of
+// id, or all of them if id is empty
+func (r *CassandraRepository) ListOverrides(id string, active bool) ([]*domain.Override, error) {
+	overrides := make([]*domain.Override, 0)
+	err := listRecords(CfOverrides, overridesRow, active, overridePageSize, func(name, value []byte) error {
+		o := &domain.Override{}
+		if err := json.Unmarshal(value, o); err != nil {
+			return fmt.Errorf("Failed to unmarshal override %s: %v", name, err)
+		}
+		if id == "" || o.Id == id {
+			overrides = append(overrides, o)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
 // ChangeLog returns a list of changesets within a certain time range
 func (r *CassandraRepository) ChangeLog(start, end time.Time, count int, lastId string) ([]*domain.ChangeSet, string, error) {
 	iter := changeTs.ReversedIterator(start, end, lastId, "")
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HailoOSS/config-service/domain"
+)
+
+func TestMatchLive(t *testing.T) {
+	now := time.Now()
+	// Written before revisions were kept, so there is a config row but no revisions row
+	live := []*domain.ChangeSet{{Id: "service.foo", Body: []byte(`{"a":1}`), Timestamp: now}}
+
+	atRevision := func(revision int64) func(cs *domain.ChangeSet) bool {
+		return func(cs *domain.ChangeSet) bool { return cs.Revision == revision }
+	}
+	at := func(t time.Time) func(cs *domain.ChangeSet) bool {
+		return func(cs *domain.ChangeSet) bool { return !cs.Timestamp.After(t) }
+	}
+
+	testCases := []struct {
+		live  []*domain.ChangeSet
+		match func(cs *domain.ChangeSet) bool
+		found bool
+	}{
+		{live, atRevision(0), true},
+		{live, atRevision(1), false},
+		{live, at(now), true},
+		{live, at(now.Add(time.Hour)), true},
+		{live, at(now.Add(-time.Hour)), false},
+		{nil, atRevision(0), false},
+		{nil, at(now), false},
+	}
+
+	for i, tc := range testCases {
+		cs, err := matchLive(tc.live, tc.match)
+		if !tc.found {
+			if err != domain.ErrRevisionNotFound {
+				t.Errorf("Test case %d: expected ErrRevisionNotFound, got %v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if cs != tc.live[0] {
+			t.Errorf("Test case %d: expected the config row, got %v", i, cs)
+		}
+	}
+}
+
+func TestUnrecorded(t *testing.T) {
+	live := []*domain.ChangeSet{
+		{Id: "service.foo"},
+		{Id: "service.bar", Revision: 3},
+	}
+
+	css := unrecorded(live)
+	if len(css) != 1 || css[0].Id != "service.foo" {
+		t.Errorf("Expected only service.foo to be unrecorded, got %v", css)
+	}
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	pathpkg "path"
+	"strings"
+)
+
+const (
+	// AclId is the config ID under which the rules restricting who may change which
+	// IDs and paths are defined, eg:
+	// {"rules":[{"id":"CITY:*","roles":["OPS-CITY"]},{"id":"H2:BASE","path":"hailo/service/cassandra","roles":["PLATFORM"]}]}
+	AclId = "ACL"
+)
+
+// AclRule restricts changes to the IDs matching a pattern, at or beneath a path, to
+// callers with one of the roles or user IDs given
+type AclRule struct {
+	// Id is a pattern matched against the whole ID, where * matches any characters
+	Id string `json:"id"`
+	// Path is "/" separated, and defaults to the whole config
+	Path string `json:"path,omitempty"`
+	// Roles, any of which allow a change
+	Roles []string `json:"roles,omitempty"`
+	// Users are the IDs of users, or services, allowed to make a change
+	Users []string `json:"users,omitempty"`
+}
+
+func (r *AclRule) String() string {
+	if r.Path == "" {
+		return r.Id
+	}
+	return r.Id + "/" + r.Path
+}
+
+// aclDefinition is the body stored under AclId
+type aclDefinition struct {
+	Rules []*AclRule `json:"rules"`
+}
+
+// AccessDeniedError is returned when a caller may not change some path of an ID
+type AccessDeniedError struct {
+	Id   string
+	Path string
+	// Rule is the rule which denied access
+	Rule *AclRule
+}
+
+func (e *AccessDeniedError) Error() string {
+	var allowed []string
+	for _, role := range e.Rule.Roles {
+		allowed = append(allowed, "role "+role)
+	}
+	for _, user := range e.Rule.Users {
+		allowed = append(allowed, "user "+user)
+	}
+	return fmt.Sprintf("Not allowed to change %q at %q: %s may only be changed by %s",
+		e.Id, e.Path, e.Rule, strings.Join(allowed, ", "))
+}
+
+// parseAcl returns the rules of an ACL definition
+func parseAcl(body []byte) ([]*AclRule, error) {
+	def := &aclDefinition{}
+	if err := json.Unmarshal(body, def); err != nil {
+		return nil, &ValidationError{Violations: []*Violation{{Message: fmt.Sprintf("ACL is not valid: %v", err)}}}
+	}
+
+	var violations []*Violation
+	for i, rule := range def.Rules {
+		if _, err := pathpkg.Match(rule.Id, ""); rule.Id == "" || err != nil {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("rules/%d/id", i),
+				Message: fmt.Sprintf("Invalid ID pattern %q", rule.Id),
+			})
+		}
+		if len(rule.Roles) == 0 && len(rule.Users) == 0 {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("rules/%d", i),
+				Message: "Rule should allow at least one role or user",
+			})
+		}
+		rule.Path = strings.Trim(rule.Path, "/")
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return def.Rules, nil
+}
+
+// checkAcl makes sure a change to the ACL is valid
+func checkAcl(id string, body []byte) error {
+	if id != AclId {
+		return nil
+	}
+	_, err := parseAcl(body)
+	return err
+}
+
+// ReadAcl returns the stored ACL rules, of which there are none until some are stored
+func ReadAcl() ([]*AclRule, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{AclId})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting ACL from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return nil, nil
+	}
+	return parseAcl(configs[0].Body)
+}
+
+// pathsOverlap returns true if either "/" separated path is at or beneath the other
+func pathsOverlap(a, b string) bool {
+	if a == "" || b == "" || a == b {
+		return true
+	}
+	return strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// allows returns true if the rule lets user, who has the roles hasRole says, make a change
+func (r *AclRule) allows(user string, hasRole func(role string) bool) bool {
+	for _, u := range r.Users {
+		if u == user {
+			return true
+		}
+	}
+	for _, role := range r.Roles {
+		if hasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAccess makes sure user, who has the roles hasRole says, may change each of paths
+// within id. Every rule covering a path, whether at, above or beneath it, must allow the
+// change, otherwise an AccessDeniedError gives the reason.
+func CheckAccess(id string, paths []string, user string, hasRole func(role string) bool) error {
+	rules, err := ReadAcl()
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if ok, _ := pathpkg.Match(rule.Id, id); !ok {
+			continue
+		}
+		for _, p := range paths {
+			p = strings.Trim(p, "/")
+			if pathsOverlap(rule.Path, p) && !rule.allows(user, hasRole) {
+				return &AccessDeniedError{Id: id, Path: p, Rule: rule}
+			}
+		}
+	}
+	return nil
+}
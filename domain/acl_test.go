@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+)
+
+func (s *DomainSuite) TestCheckAccess() {
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			AclId: &ChangeSet{
+				Id: AclId,
+				Body: []byte(`{"rules":[
+					{"id":"CITY:*","roles":["OPS-CITY"]},
+					{"id":"H2:BASE","path":"hailo/service/cassandra","roles":["PLATFORM"],"users":["dave"]}
+				]}`),
+				Timestamp: time.Now(),
+			},
+		},
+	}
+
+	roles := func(has ...string) func(string) bool {
+		return func(role string) bool {
+			for _, r := range has {
+				if r == role {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	testCases := []struct {
+		id      string
+		paths   []string
+		user    string
+		hasRole func(string) bool
+		allowed bool
+	}{
+		{"CITY:LON", []string{""}, "bob", roles("OPS-CITY"), true},
+		{"CITY:LON", []string{"foo"}, "bob", roles("ADMIN"), false},
+		{"H2:BASE", []string{"hailo/service/zookeeper"}, "bob", roles("ADMIN"), true},
+		{"H2:BASE", []string{"hailo/service/cassandra/hosts"}, "bob", roles("ADMIN"), false},
+		{"H2:BASE", []string{"hailo/service/cassandra/hosts"}, "bob", roles("PLATFORM"), true},
+		{"H2:BASE", []string{"hailo/service/cassandra/hosts"}, "dave", roles(), true},
+		// Replacing a path above a protected one changes it too
+		{"H2:BASE", []string{"hailo"}, "bob", roles("ADMIN"), false},
+		{"H2:BASE", []string{""}, "bob", roles("ADMIN"), false},
+		{"H2:BASE", []string{"hailo/service/cassandrafoo"}, "bob", roles("ADMIN"), true},
+		{"H2:BASE", []string{"hailo/service/zookeeper", "hailo/service/cassandra"}, "bob", roles("ADMIN"), false},
+	}
+
+	for i, tc := range testCases {
+		err := CheckAccess(tc.id, tc.paths, tc.user, tc.hasRole)
+		if tc.allowed {
+			s.NoError(err, "Unexpected error for testcase %v", i)
+			continue
+		}
+		_, ok := err.(*AccessDeniedError)
+		s.True(ok, "Expected access denied for testcase %v, got %v", i, err)
+	}
+
+	for _, body := range []string{`{"rules":[{"id":"[","roles":["A"]}]}`, `{"rules":[{"id":"A"}]}`, `{"rules":{}}`} {
+		_, ok := checkAcl(AclId, []byte(body)).(*ValidationError)
+		s.True(ok, "Expected invalid ACL %s to fail", body)
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	platformsync "github.com/HailoOSS/service/sync"
+)
+
+// BatchUpdate is a single update within BatchUpdateConfig
+type BatchUpdate struct {
+	// ChangeId is the unique ID for this change
+	ChangeId string
+	Id       string
+	Path     string
+	Config   []byte
+	// Opts apply to this update alone
+	Opts *WriteOptions
+}
+
+// BatchError is returned when an update within a batch fails, in which case nothing is written
+type BatchError struct {
+	// Index of the failing update within the batch
+	Index int
+	Id    string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("Update %d to %s failed: %v", e.Index, e.Id, e.Err)
+}
+
+// BatchUpdateConfig applies several updates, possibly to different IDs, all-or-nothing.
+// The region lock for every ID is taken in a deterministic order, so that concurrent
+// batches cannot deadlock, and every change is checked and validated, with compiled
+// validation seeing the other changes in the batch, before they are written together.
+// Each change records batchId. Later updates to the same ID build on earlier ones.
+// It returns the changes written, in order.
+func BatchUpdateConfig(batchId, userMech, userId, message string, updates []*BatchUpdate) ([]*ChangeSet, error) {
+	nodes := make([]interface{}, len(updates))
+	ids := make([]string, 0, len(updates))
+	seen := make(map[string]bool, len(updates))
+	for i, u := range updates {
+		if err := json.Unmarshal(u.Config, &nodes[i]); err != nil {
+			return nil, &BatchError{Index: i, Id: u.Id, Err: fmt.Errorf("New value is not valid JSON: %v", err)}
+		}
+		if !seen[u.Id] {
+			seen[u.Id] = true
+			ids = append(ids, u.Id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		lock, err := platformsync.RegionLock([]byte(id))
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+	}
+
+	configs, err := DefaultRepository.ReadConfig(ids)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	current := make(map[string][]*ChangeSet, len(ids))
+	for _, cs := range configs {
+		current[cs.Id] = []*ChangeSet{cs}
+	}
+
+	changes := make([]*ChangeSet, len(updates))
+	for i, u := range updates {
+		cs, err := updateChangeSet(current[u.Id], u.ChangeId, u.Id, u.Path, userMech, userId, message, u.Config, nodes[i], u.Opts)
+		if err != nil {
+			return nil, &BatchError{Index: i, Id: u.Id, Err: err}
+		}
+		cs.BatchId = batchId
+		changes[i] = cs
+		current[u.Id] = []*ChangeSet{cs}
+	}
+
+	pending := make(map[string][]byte, len(ids))
+	for id, configs := range current {
+		pending[id] = configs[0].Body
+	}
+	for i, cs := range changes {
+		if err := checkFreeze(cs, updates[i].Opts); err != nil {
+			return nil, &BatchError{Index: i, Id: cs.Id, Err: err}
+		}
+		if err := prepareConfig(cs, updates[i].Opts, pending); err != nil {
+			return nil, &BatchError{Index: i, Id: cs.Id, Err: err}
+		}
+	}
+
+	if err := DefaultRepository.UpdateConfigs(changes); err != nil {
+		return nil, fmt.Errorf("Error saving config: %v", err)
+	}
+	notifyChange(changes...)
+
+	return changes, nil
+}
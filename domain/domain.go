@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,16 +16,38 @@ import (
 
 const (
 	sjsonnull = "null"
+
+	// changeLogPageSize is how many changes ChangesSince reads at a time
+	changeLogPageSize = 100
 )
 
 var (
-	ErrPathNotFound   = errors.New("Config path not found")
-	ErrIdNotFound     = errors.New("Config ID not found")
-	DefaultRepository ConfigRepository
+	ErrPathNotFound     = errors.New("Config path not found")
+	ErrIdNotFound       = errors.New("Config ID not found")
+	ErrRevisionNotFound = errors.New("Config revision not found")
+	ErrConfigChanged    = errors.New("Config has changed since it was read")
+	ErrIdExists         = errors.New("Config ID already exists")
+	DefaultRepository   ConfigRepository
 
 	emptyConfig = []byte("{}")
 )
 
+// ErrPathConflict is returned when a path runs through a value which is not an object,
+// or when strictly compiling, where one layer has an object and another has some other value
+type ErrPathConflict struct {
+	// Path is where the conflict was found
+	Path string
+	// Ids are the configs in conflict, when compiling
+	Ids []string
+}
+
+func (e *ErrPathConflict) Error() string {
+	if len(e.Ids) == 0 {
+		return fmt.Sprintf("Config path conflict at %s: existing value is not an object", e.Path)
+	}
+	return fmt.Sprintf("Config path conflict at %s between %s", e.Path, strings.Join(e.Ids, ", "))
+}
+
 // ChangeSet represents some change to our config
 type ChangeSet struct {
 	// Id is a unique ID for the change
@@ -44,13 +68,122 @@ type ChangeSet struct {
 	Path string `name:"path" json:"path"`
 	// Old value for the config
 	OldConfig []byte `name:"oldConfig" json:"oldConfig"`
+	// SkipValidation is set if the change was forced through without schema validation
+	SkipValidation bool `name:"skipValidation" json:"skipValidation"`
+	// Revision increases by one with every change to an ID, starting from 1
+	Revision int64 `name:"revision" json:"revision"`
+	// RolledBack is set if this change was a rollback or undelete, restoring RolledBackTo,
+	// which may be revision 0
+	RolledBack   bool  `name:"rolledBack" json:"rolledBack"`
+	RolledBackTo int64 `name:"rolledBackTo" json:"rolledBackTo"`
+	// Patch is the JSON Patch applied, if this change was a patch
+	Patch []byte `name:"patch" json:"patch"`
+	// MergePatch is the JSON Merge Patch applied at the path, if this change was a merge
+	MergePatch []byte `name:"mergePatch" json:"mergePatch"`
+	// NewConfig is the resulting config at the path, if this change was a merge
+	NewConfig []byte `name:"newConfig" json:"newConfig"`
+	// BatchId is shared by all the changes written together by a batch update
+	BatchId string `name:"batchId" json:"batchId"`
+	// Deleted is set if this change deleted the whole ID, in which case OldConfig holds
+	// the config deleted
+	Deleted bool `name:"deleted" json:"deleted"`
+	// BreakGlass is set if the change was made regardless of any freeze window
+	BreakGlass bool `name:"breakGlass" json:"breakGlass"`
+	// NoReload is set if the change was neither broadcast nor published as a platform event
+	NoReload bool `name:"noReload" json:"noReload"`
+	// ExpectedHash and ExpectedRevision are those the writer expected to be changing, if given
+	ExpectedHash     string `name:"expectedHash" json:"expectedHash"`
+	ExpectedRevision int64  `name:"expectedRevision" json:"expectedRevision"`
+	// ScheduleId is that of the scheduled change, if this change was made by the scheduler
+	ScheduleId string `name:"scheduleId" json:"scheduleId"`
+	// ExpiresAt is when the change is reverted, if it was a temporary override
+	ExpiresAt time.Time `name:"expiresAt" json:"expiresAt"`
+	// Reverts is the ChangeId of the temporary override this change reverted, once expired
+	Reverts string `name:"reverts" json:"reverts"`
+	// ProposalId is that of the proposal, if this change was made by approving one, in
+	// which case the reviewer is whoever approved it
+	ProposalId    string `name:"proposalId" json:"proposalId"`
+	ReviewerMech  string `name:"reviewerMech" json:"reviewerMech"`
+	ReviewerId    string `name:"reviewerId" json:"reviewerId"`
+	ReviewMessage string `name:"reviewMessage" json:"reviewMessage"`
+}
+
+// Summary returns a copy of the change with the metadata only, and none of the config
+func (cs *ChangeSet) Summary() *ChangeSet {
+	return &ChangeSet{
+		Id:               cs.Id,
+		Timestamp:        cs.Timestamp,
+		UserMech:         cs.UserMech,
+		UserId:           cs.UserId,
+		Message:          cs.Message,
+		ChangeId:         cs.ChangeId,
+		Path:             cs.Path,
+		SkipValidation:   cs.SkipValidation,
+		Revision:         cs.Revision,
+		RolledBack:       cs.RolledBack,
+		RolledBackTo:     cs.RolledBackTo,
+		BatchId:          cs.BatchId,
+		BreakGlass:       cs.BreakGlass,
+		NoReload:         cs.NoReload,
+		ExpectedHash:     cs.ExpectedHash,
+		ExpectedRevision: cs.ExpectedRevision,
+		ScheduleId:       cs.ScheduleId,
+		ExpiresAt:        cs.ExpiresAt,
+		Reverts:          cs.Reverts,
+		ProposalId:       cs.ProposalId,
+		ReviewerMech:     cs.ReviewerMech,
+		ReviewerId:       cs.ReviewerId,
+		ReviewMessage:    cs.ReviewMessage,
+	}
+}
+
+// ConfigAtPath returns the config at the path of the change, as it was after the change,
+// or ErrPathNotFound if the change removed it
+func (cs *ChangeSet) ConfigAtPath() ([]byte, error) {
+	return readConfigAtPath(cs.Body, cs.Path)
 }
 
 type ConfigRepository interface {
 	ReadConfig(ids []string) ([]*ChangeSet, error)
+	// ReadConfigAtRevision returns the whole config for id as it was at the given revision,
+	// or ErrRevisionNotFound
+	ReadConfigAtRevision(id string, revision int64) (*ChangeSet, error)
+	// ReadConfigAt returns the whole config for id as it was at time t, or ErrRevisionNotFound
+	ReadConfigAt(id string, t time.Time) (*ChangeSet, error)
+	// UpdateConfig saves the change as the latest version of its ID, also keeping it as
+	// an immutable revision
 	UpdateConfig(cs *ChangeSet) error
+	// UpdateConfigs saves several changes so that either all or none are applied.
+	// Where there are several changes to one ID, the last is its latest version.
+	UpdateConfigs(css []*ChangeSet) error
 	ChangeLog(start, end time.Time, count int, lastId string) ([]*ChangeSet, string, error)
 	ServiceChangeLog(id string, start, end time.Time, count int, lastId string) ([]*ChangeSet, string, error)
+	// DeleteId removes the ID from the config store and any listings, recording cs,
+	// which should be marked Deleted, as its latest revision
+	DeleteId(cs *ChangeSet) error
+	// ListIds returns up to count IDs starting with prefix, in order, after cursor if
+	// given, as summaries of their latest change. It also returns the cursor for the next
+	// page, which is empty if there are no more.
+	ListIds(prefix, cursor string, count int) ([]*ChangeSet, string, error)
+	// SaveProposal creates or replaces a proposed change
+	SaveProposal(p *Proposal) error
+	// ReadProposal returns the proposal, or ErrProposalNotFound
+	ReadProposal(proposalId string) (*Proposal, error)
+	// ListProposals returns every proposal to change id, or any ID if empty, in any order.
+	// If pending is set, only those yet to be reviewed are returned.
+	ListProposals(id string, pending bool) ([]*Proposal, error)
+	// SaveScheduledChange creates or replaces a scheduled change
+	SaveScheduledChange(sc *ScheduledChange) error
+	// ReadScheduledChange returns the scheduled change, or ErrScheduleNotFound
+	ReadScheduledChange(scheduleId string) (*ScheduledChange, error)
+	// ListScheduledChanges returns every change scheduled for id, or any ID if empty, in
+	// any order. If pending is set, only those yet to be applied are returned.
+	ListScheduledChanges(id string, pending bool) ([]*ScheduledChange, error)
+	// SaveOverride creates or replaces a temporary override
+	SaveOverride(o *Override) error
+	// ListOverrides returns every temporary override of id, or any ID if empty, in any order.
+	// If active is set, only those still in place are returned.
+	ListOverrides(id string, active bool) ([]*Override, error)
 }
 
 func readConfigAtPath(body []byte, path string) ([]byte, error) {
@@ -96,123 +229,454 @@ func ReadConfig(id, path string) ([]byte, *ChangeSet, error) {
 	return b, configs[0], err
 }
 
+// ReadConfigAtRevision is like ReadConfig, but returns the config as it was at some revision
+func ReadConfigAtRevision(id, path string, revision int64) ([]byte, *ChangeSet, error) {
+	cs, err := DefaultRepository.ReadConfigAtRevision(id, revision)
+	if err == ErrRevisionNotFound {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if cs.Deleted {
+		return nil, nil, ErrIdNotFound
+	}
+
+	b, err := readConfigAtPath(cs.Body, path)
+	return b, cs, err
+}
+
+// ReadConfigAt is like ReadConfig, but returns the config as it was at time t
+func ReadConfigAt(id, path string, t time.Time) ([]byte, *ChangeSet, error) {
+	cs, err := DefaultRepository.ReadConfigAt(id, t)
+	if err == ErrRevisionNotFound {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if cs.Deleted {
+		return nil, nil, ErrIdNotFound
+	}
+
+	b, err := readConfigAtPath(cs.Body, path)
+	return b, cs, err
+}
+
+// nextRevision returns the revision for a change to id, whose config was read as configs.
+// If id does not exist it may have been deleted, in which case its revisions carry on.
+func nextRevision(id string, configs []*ChangeSet) (int64, error) {
+	if len(configs) == 1 {
+		return configs[0].Revision + 1, nil
+	}
+	latest, err := DefaultRepository.ReadConfigAt(id, time.Now())
+	if err == ErrRevisionNotFound {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	return latest.Revision + 1, nil
+}
+
 // ChangeLog returns a time series list of changes
 func ChangeLog(start, end time.Time, count int, lastId string) ([]*ChangeSet, string, error) {
 	chs, last, err := DefaultRepository.ChangeLog(start, end, count, lastId)
 	return chs, last, err
 }
 
+// ChangesSince returns every change made from since until now, oldest first
+func ChangesSince(since time.Time) ([]*ChangeSet, error) {
+	var css []*ChangeSet
+	end := time.Now()
+	cursor := ""
+	for {
+		page, last, err := DefaultRepository.ChangeLog(since, end, changeLogPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		css = append(css, page...)
+		if last == "" || len(page) < changeLogPageSize {
+			break
+		}
+		cursor = last
+	}
+
+	// The change log is newest first
+	for i, j := 0, len(css)-1; i < j; i, j = i+1, j-1 {
+		css[i], css[j] = css[j], css[i]
+	}
+	return css, nil
+}
+
 // ChangeLog returns a time series list of changes for the given ID
 func ServiceChangeLog(id string, start, end time.Time, count int, lastId string) ([]*ChangeSet, string, error) {
 	chs, last, err := DefaultRepository.ServiceChangeLog(id, start, end, count, lastId)
 	return chs, last, err
 }
 
-// mergeMap starts with "a" and recursivley adds "b" on top
-// "a" will be modified
-// bId is the id of "b" which is used for explaining
-func mergeMap(a, b map[string]interface{}, bId string, stack []string, explain bool) []string {
-	for k, v := range b {
-		m, ok := v.(map[string]interface{})
-		if ok {
-			// Keep walking
-			stack = append(stack, k)
-			stack = mergeMap(a, m, bId, stack, explain)
-		} else {
-			// We're at a "leaf"
-			// Walk down "a" creating nodes if we need to
-			// and set the value
-			anode := a
-			for _, pos := range stack {
-				if next, ok := anode[pos]; ok {
-					anode = next.(map[string]interface{})
-					continue
-				}
-				// Doesn't exist, create
-				anode[pos] = make(map[string]interface{})
-				anode = anode[pos].(map[string]interface{})
-			}
+// ListIds returns a page of the IDs which exist with the given prefix, eg: "H2:BASE:",
+// along with the metadata of their latest change
+func ListIds(prefix, cursor string, count int) ([]*ChangeSet, string, error) {
+	if count <= 0 {
+		return nil, "", fmt.Errorf("Count of IDs to list must be positive, not %v", count)
+	}
+	return DefaultRepository.ListIds(prefix, cursor, count)
+}
 
-			// Relace final node
-			if explain {
-				// In explain mode we set all values to the id
-				// of the config they came from
-				anode[k] = bId
-			} else {
-				anode[k] = v
+// CompileOptions modify how config is compiled. A nil *CompileOptions applies the defaults.
+type CompileOptions struct {
+	// Strict returns an *ErrPathConflict, listing the path and ids involved, if the
+	// configs disagree on the type of a node, rather than the later config winning
+	Strict bool
+	// AsOf compiles each id as it was at this time, rather than its latest revision
+	AsOf time.Time
+	// Vars are the values of ${var:name} references, eg: the region. If nil, these
+	// references are left as they are.
+	Vars map[string]string
+	// RevealSecrets decrypts referenced secrets, which are otherwise masked. Only
+	// authorised callers should be given secrets.
+	RevealSecrets bool
+}
+
+// readConfigsAt returns the configs for ids as they were at time t, or their latest
+// revisions if t is zero. Like ReadConfig, ids which did not exist are omitted, while
+// it returns ErrRevisionNotFound if an ID existed but its config at t is unknown.
+func readConfigsAt(ids []string, t time.Time) ([]*ChangeSet, error) {
+	if t.IsZero() {
+		return DefaultRepository.ReadConfig(ids)
+	}
+
+	configs := make([]*ChangeSet, 0, len(ids))
+	for _, id := range ids {
+		cs, err := DefaultRepository.ReadConfigAt(id, t)
+		if err == ErrRevisionNotFound {
+			created, err := createdAfter(id, t)
+			if err != nil {
+				return nil, err
+			}
+			if !created {
+				return nil, ErrRevisionNotFound
 			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !cs.Deleted {
+			configs = append(configs, cs)
 		}
 	}
-	// Pop item off stack
-	if len(stack) > 0 {
-		stack = stack[:len(stack)-1]
+	return configs, nil
+}
+
+// createdAfter returns true if id did not exist at time t, as it has never existed or
+// its first revision came later. Config written before revisions were kept is revision 0.
+func createdAfter(id string, t time.Time) (bool, error) {
+	for _, revision := range []int64{0, 1} {
+		first, err := DefaultRepository.ReadConfigAtRevision(id, revision)
+		if err == ErrRevisionNotFound {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		return first.Timestamp.After(t), nil
 	}
-	return stack
+
+	// Without a first revision there is no knowing what it was before, unless it has
+	// never existed at all
+	configs, err := DefaultRepository.ReadConfig([]string{id})
+	if err != nil {
+		return false, err
+	}
+	return len(configs) == 0, nil
 }
 
-func compileConfig(ids []string, path string, explain bool) ([]byte, error) {
-	if explain {
-		// When explaining, we merge the first item in the list with itself
-		// This ensures that to start with, all values appear to have come from
-		// "a"
-		ids = append(ids[:1], ids...)
+// compileConfig returns the compiled config, along with the configs which were merged to make it
+func compileConfig(ids []string, path string, explain bool, opts *CompileOptions) ([]byte, []*ChangeSet, error) {
+	if opts == nil {
+		opts = &CompileOptions{}
 	}
 
-	configs, err := DefaultRepository.ReadConfig(ids)
+	configs, err := readConfigsAt(ids, opts.AsOf)
+	if err == ErrRevisionNotFound {
+		return nil, nil, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("Error getting configs: %v", err)
+		return nil, nil, fmt.Errorf("Error getting configs: %v", err)
 	}
 
-	var compiled map[string]interface{}
+	compiled, err := mergeConfigs(configs, explain, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if len(configs) > 0 {
-		err = json.Unmarshal(configs[0].Body, &compiled)
-		if err != nil {
-			return nil, fmt.Errorf("Error unmarshalling config: %v", err)
+	data, err := json.Marshal(compiled)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error marshalling compiled JSON: %v", err)
+	}
+
+	b, err := readConfigAtPath(data, path)
+	if err == ErrPathNotFound {
+		return emptyConfig, configs, nil
+	}
+	return b, configs, err
+}
+
+// withBody returns configs with the body of id swapped for the one given, adding it
+// to the end if id is not present
+func withBody(configs []*ChangeSet, id string, body []byte) []*ChangeSet {
+	replaced := make([]*ChangeSet, len(configs))
+	found := false
+	for i, cs := range configs {
+		replaced[i] = cs
+		if cs.Id == id {
+			replaced[i] = &ChangeSet{Id: id, Body: body}
+			found = true
 		}
+	}
+	if !found {
+		replaced = append(replaced, &ChangeSet{Id: id, Body: body})
+	}
+	return replaced
+}
 
-		// Merge!
-		// Skip the first one since it's the base we're starting from
-		for i := 1; i < len(configs); i++ {
-			var config map[string]interface{}
-			err := json.Unmarshal(configs[i].Body, &config)
-			if err != nil {
-				return nil, fmt.Errorf("Error unmarshalling config: %v", err)
+// CompileConfigWithChange compiles ids both as they stand, and as they would be if
+// data were written to id at path, returning the two versions of the compiled config
+func CompileConfigWithChange(ids []string, id, path string, data []byte) (before, after []byte, err error) {
+	var newNode interface{}
+	if err := json.Unmarshal(data, &newNode); err != nil {
+		return nil, nil, fmt.Errorf("New value is not valid JSON: %v", err)
+	}
+
+	configs, err := DefaultRepository.ReadConfig(ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error getting configs: %v", err)
+	}
+
+	body := data
+	if path != "" {
+		var current []byte
+		for _, cs := range configs {
+			if cs.Id == id {
+				current = cs.Body
 			}
-			mergeMap(compiled, config, configs[i].Id, make([]string, 0), explain)
 		}
+		if body, err = setConfigAtPath(current, path, newNode); err != nil {
+			return nil, nil, err
+		}
+	} else if _, ok := newNode.(map[string]interface{}); !ok {
+		return nil, nil, fmt.Errorf("Top level config should be a JSON object")
 	}
 
-	data, err := json.Marshal(compiled)
+	compiled, err := mergeConfigs(configs, false, nil)
 	if err != nil {
-		return nil, fmt.Errorf("Error marshalling compiled JSON: %v", err)
+		return nil, nil, err
+	}
+	if before, err = json.Marshal(compiled); err != nil {
+		return nil, nil, fmt.Errorf("Error marshalling compiled JSON: %v", err)
 	}
 
-	b, err := readConfigAtPath(data, path)
-	if err == ErrPathNotFound {
-		return emptyConfig, nil
+	if compiled, err = mergeConfigs(withBody(configs, id, body), false, nil); err != nil {
+		return nil, nil, err
 	}
-	return b, err
+	if after, err = json.Marshal(compiled); err != nil {
+		return nil, nil, fmt.Errorf("Error marshalling compiled JSON: %v", err)
+	}
+
+	return before, after, nil
 }
 
 // CompileConfig will combine multiple configs together.
+// Where one config has an object and a later one has some other value, or vice
+// versa, the later config wins, replacing the whole subtree.
 func CompileConfig(ids []string, path string) ([]byte, error) {
-	return compileConfig(ids, path, false)
+	b, _, err := compileConfig(ids, path, false, nil)
+	return b, err
+}
+
+// CompileConfigWithOptions is like CompileConfig, but also returns the configs which
+// were merged, whose change ids and revisions identify exactly what was compiled
+func CompileConfigWithOptions(ids []string, path string, opts *CompileOptions) ([]byte, []*ChangeSet, error) {
+	return compileConfig(ids, path, false, opts)
 }
 
 // ExplainConfig returns the compiled config except that instead of showing
 // the original values, it shows which id was responsible for setting it
 func ExplainConfig(ids []string, path string) ([]byte, error) {
-	return compileConfig(ids, path, true)
+	b, _, err := compileConfig(ids, path, true, nil)
+	return b, err
+}
+
+// ExplainConfigWithOptions is like ExplainConfig, but also returns the configs which were merged
+func ExplainConfigWithOptions(ids []string, path string, opts *CompileOptions) ([]byte, []*ChangeSet, error) {
+	return compileConfig(ids, path, true, opts)
+}
+
+// WriteOptions modify how a change is applied. A nil *WriteOptions applies the defaults.
+type WriteOptions struct {
+	// SkipValidation writes the change even if it violates registered schemas.
+	// It is recorded against the change, and is intended for emergencies only.
+	SkipValidation bool
+	// ValidateCompiled additionally validates the compiled config of the standard
+	// H2 hierarchy implied by the ID being changed
+	ValidateCompiled bool
+	// ExpectedHash, if set, fails the write with ErrConfigChanged unless the hash of
+	// the config currently at the path, as returned by read, matches
+	ExpectedHash string
+	// ExpectedRevision, if set, fails the write with ErrConfigChanged unless the ID
+	// is currently at this revision
+	ExpectedRevision int64
+	// Merge deep merges an update into the existing node at the path as an RFC 7396
+	// JSON Merge Patch, where null deletes a key, rather than replacing the node
+	Merge bool
+	// BreakGlass makes the change even during a freeze window. It is recorded against
+	// the change, which must have a message, and is intended for emergencies only.
+	BreakGlass bool
+	// NoReload is recorded against the change, which is then neither broadcast nor
+	// published as a platform event
+	NoReload bool
+	// ScheduleId is recorded against the change, if it is being made by the scheduler
+	ScheduleId string
+	// ExpiresAt, if set, makes an update a temporary override, reverted to the config at
+	// the path from before it once the time passes
+	ExpiresAt time.Time
+	// Reverts is recorded against the change, if it is reverting an expired override
+	Reverts string
+	// setSecret is set by SetSecret, which is the only way secrets may be written
+	setSecret bool
+	// breakGlassMessage justifies breaking glass in place of the message of the change,
+	// when made by someone else, eg: the approver of a proposal
+	breakGlassMessage string
+}
+
+// ConfigHash hashes config (JSON) using md5
+func ConfigHash(config []byte) string {
+	h := md5.New()
+	h.Write(config)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checkExpected makes sure the config read as configs is what the writer expected
+// to be changing, so that concurrent writers don't silently clobber each other
+func checkExpected(configs []*ChangeSet, path string, opts *WriteOptions) error {
+	if opts == nil || (opts.ExpectedHash == "" && opts.ExpectedRevision == 0) {
+		return nil
+	}
+
+	var current *ChangeSet
+	if len(configs) == 1 {
+		current = configs[0]
+	}
+
+	if opts.ExpectedRevision != 0 && (current == nil || current.Revision != opts.ExpectedRevision) {
+		return ErrConfigChanged
+	}
+
+	if opts.ExpectedHash != "" {
+		if current == nil {
+			return ErrConfigChanged
+		}
+		b, err := readConfigAtPath(current.Body, path)
+		if err != nil && err != ErrPathNotFound {
+			return fmt.Errorf("Error getting config at path %s : %v", path, err)
+		}
+		if ConfigHash(b) != opts.ExpectedHash {
+			return ErrConfigChanged
+		}
+	}
+
+	return nil
+}
+
+// saveConfig performs the checks common to all writes and then persists the change
+func saveConfig(cs *ChangeSet, opts *WriteOptions) error {
+	if err := checkFreeze(cs, opts); err != nil {
+		return err
+	}
+	if opts != nil {
+		cs.ScheduleId = opts.ScheduleId
+		cs.Reverts = opts.Reverts
+	}
+	if err := prepareConfig(cs, opts, nil); err != nil {
+		return err
+	}
+
+	if err := DefaultRepository.UpdateConfig(cs); err != nil {
+		return fmt.Errorf("Error saving config: %v", err)
+	}
+	notifyChange(cs)
+
+	return nil
+}
+
+// prepareConfig performs the checks common to all writes. pending holds the new bodies
+// of any other IDs being written alongside this one, keyed by ID.
+func prepareConfig(cs *ChangeSet, opts *WriteOptions, pending map[string][]byte) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+
+	if err := checkSecretsWrite(cs.Id, opts); err != nil {
+		return err
+	}
+	if err := checkSchemaRegistry(cs.Id, cs.Body); err != nil {
+		return err
+	}
+	if err := checkHierarchy(cs.Id, cs.Body); err != nil {
+		return err
+	}
+	if err := checkRedactionPolicy(cs.Id, cs.Body); err != nil {
+		return err
+	}
+	if err := checkAcl(cs.Id, cs.Body); err != nil {
+		return err
+	}
+	if err := checkApprovalPolicy(cs.Id, cs.Body); err != nil {
+		return err
+	}
+	if err := checkFreezeWindows(cs.Id, cs.Body); err != nil {
+		return err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(cs.Body, &decoded); err != nil {
+		return fmt.Errorf("Error decoding config: %v", err)
+	}
+	if err := checkDirectives(decoded, ""); err != nil {
+		return err
+	}
+
+	cs.SkipValidation = opts.SkipValidation
+	cs.NoReload = opts.NoReload
+	cs.ExpectedHash = opts.ExpectedHash
+	cs.ExpectedRevision = opts.ExpectedRevision
+	if !opts.SkipValidation {
+		if err := validateChange(cs.Id, cs.Path, cs.Body, opts.ValidateCompiled, pending); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // DeleteConfig will delete the node at the specified path
 // It will return ErrPathNotFound if the path does not exist
-func DeleteConfig(changeId, id, path, userMech, userId, message string) error {
+func DeleteConfig(changeId, id, path, userMech, userId, message string, opts *WriteOptions) error {
+	lock, err := platformsync.RegionLock([]byte(id))
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	configs, err := DefaultRepository.ReadConfig([]string{id})
 	if err != nil || len(configs) != 1 {
 		return fmt.Errorf("Error getting config with id: %v", id)
 	}
+	if err := checkExpected(configs, path, opts); err != nil {
+		return err
+	}
 
 	var decoded map[string]interface{}
 	err = json.Unmarshal(configs[0].Body, &decoded)
@@ -226,11 +690,15 @@ func DeleteConfig(changeId, id, path, userMech, userId, message string) error {
 		parts := strings.Split(path, "/")
 		node := decoded
 		ok := true
-		for _, part := range parts[:len(parts)-1] {
-			node, ok = node[part].(map[string]interface{})
-			if !ok {
+		for i, part := range parts[:len(parts)-1] {
+			next, exists := node[part]
+			if !exists || next == nil {
 				return ErrPathNotFound
 			}
+			node, ok = next.(map[string]interface{})
+			if !ok {
+				return &ErrPathConflict{Path: strings.Join(parts[:i+1], "/")}
+			}
 		}
 		delete(node, parts[len(parts)-1])
 	} else {
@@ -247,8 +715,12 @@ func DeleteConfig(changeId, id, path, userMech, userId, message string) error {
 	if err != nil {
 		return fmt.Errorf("Error reading config at path %s : %s ", path, err.Error())
 	}
+	revision, err := nextRevision(id, configs)
+	if err != nil {
+		return err
+	}
 
-	err = DefaultRepository.UpdateConfig(&ChangeSet{
+	return saveConfig(&ChangeSet{
 		Id:        id,
 		Body:      encoded,
 		Timestamp: time.Now(),
@@ -258,20 +730,267 @@ func DeleteConfig(changeId, id, path, userMech, userId, message string) error {
 		ChangeId:  changeId,
 		Path:      path,
 		OldConfig: oldConfig,
-	})
+		Revision:  revision,
+	}, opts)
+}
 
+// DeleteId deletes the whole config for id, rather than emptying it, so that it no longer
+// exists or appears in listings. The deletion is recorded as a change like any other,
+// and the config can be restored with UndeleteId.
+func DeleteId(changeId, id, userMech, userId, message string, opts *WriteOptions) (*ChangeSet, error) {
+	lock, err := platformsync.RegionLock([]byte(id))
 	if err != nil {
-		return fmt.Errorf("Error saving config: %v", err)
+		return nil, err
 	}
+	defer lock.Unlock()
 
-	return nil
+	configs, err := DefaultRepository.ReadConfig([]string{id})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return nil, ErrIdNotFound
+	}
+	if err := checkExpected(configs, "", opts); err != nil {
+		return nil, err
+	}
+	if err := checkSecretsWrite(id, opts); err != nil {
+		return nil, err
+	}
+
+	cs := &ChangeSet{
+		Id:        id,
+		Timestamp: time.Now(),
+		UserMech:  userMech,
+		UserId:    userId,
+		Message:   message,
+		ChangeId:  changeId,
+		OldConfig: configs[0].Body,
+		Revision:  configs[0].Revision + 1,
+		Deleted:   true,
+	}
+	if err := checkFreeze(cs, opts); err != nil {
+		return nil, err
+	}
+	if err := DefaultRepository.DeleteId(cs); err != nil {
+		return nil, fmt.Errorf("Error deleting config: %v", err)
+	}
+	notifyChange(cs)
+	return cs, nil
+}
+
+// UndeleteId restores the config for an id deleted by DeleteId, as a new change. It
+// returns ErrIdExists if the id exists, or ErrIdNotFound if it was never deleted.
+func UndeleteId(changeId, id, userMech, userId, message string, opts *WriteOptions) (*ChangeSet, error) {
+	lock, err := platformsync.RegionLock([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	configs, err := DefaultRepository.ReadConfig([]string{id})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if len(configs) != 0 {
+		return nil, ErrIdExists
+	}
+
+	deleted, err := DefaultRepository.ReadConfigAt(id, time.Now())
+	if err == ErrRevisionNotFound {
+		return nil, ErrIdNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if !deleted.Deleted {
+		return nil, ErrIdNotFound
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Undelete revision %v", deleted.Revision-1)
+	}
+	cs := &ChangeSet{
+		Id:           id,
+		Body:         deleted.OldConfig,
+		Timestamp:    time.Now(),
+		UserMech:     userMech,
+		UserId:       userId,
+		Message:      message,
+		ChangeId:     changeId,
+		Revision:     deleted.Revision + 1,
+		RolledBackTo: deleted.Revision - 1,
+		RolledBack:   true,
+	}
+	if err := saveConfig(cs, opts); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// RollbackTarget identifies the revision to roll back to. Only one field should be set.
+type RollbackTarget struct {
+	// Revision to restore
+	Revision int64
+	// ChangeId of the change whose revision should be restored
+	ChangeId string
+	// Undo is the number of most recent changes to undo
+	Undo int64
+}
+
+// RollbackConfig restores the whole config for id as it was at an earlier revision,
+// writing it as a new change which records the revision restored.
+// It returns ErrRevisionNotFound if the target cannot be found.
+func RollbackConfig(changeId, id, userMech, userId, message string, target *RollbackTarget, opts *WriteOptions) (*ChangeSet, error) {
+	lock, err := platformsync.RegionLock([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	configs, err := DefaultRepository.ReadConfig([]string{id})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return nil, ErrIdNotFound
+	}
+
+	revision, err := rollbackRevision(configs[0], target)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := DefaultRepository.ReadConfigAtRevision(id, revision)
+	if err == ErrRevisionNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+
+	if restored.Deleted {
+		return nil, ErrRevisionNotFound
+	}
+	next, err := nextRevision(id, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Rollback to revision %v", revision)
+	}
+	cs := &ChangeSet{
+		Id:           id,
+		Body:         restored.Body,
+		Timestamp:    time.Now(),
+		UserMech:     userMech,
+		UserId:       userId,
+		Message:      message,
+		ChangeId:     changeId,
+		OldConfig:    configs[0].Body,
+		Revision:     next,
+		RolledBackTo: revision,
+		RolledBack:   true,
+	}
+	if err := saveConfig(cs, opts); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// rollbackRevision works out which revision of current the target refers to
+func rollbackRevision(current *ChangeSet, target *RollbackTarget) (int64, error) {
+	revision := target.Revision
+	switch {
+	case target.ChangeId != "":
+		// Walk back through history until we find the change
+		for revision = current.Revision; revision >= 0; revision-- {
+			cs, err := DefaultRepository.ReadConfigAtRevision(current.Id, revision)
+			if err == ErrRevisionNotFound {
+				return 0, err
+			}
+			if err != nil {
+				return 0, fmt.Errorf("Error getting config from DAO: %v", err)
+			}
+			if cs.ChangeId == target.ChangeId {
+				return revision, nil
+			}
+		}
+	case target.Undo < 0:
+		return 0, ErrRevisionNotFound
+	case target.Undo > 0:
+		revision = current.Revision - target.Undo
+	}
+
+	// Config last written before revisions were kept is revision 0
+	if revision < 0 || revision > current.Revision {
+		return 0, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// PatchConfig applies an RFC 6902 JSON Patch to the whole config for id, as a single change.
+// Either every operation is applied or none are, and "test" operations can be used as
+// preconditions. It returns a *PatchError if the patch cannot be applied.
+func PatchConfig(changeId, id, userMech, userId, message string, patch []byte, opts *WriteOptions) error {
+	lock, err := platformsync.RegionLock([]byte(id))
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	configs, err := DefaultRepository.ReadConfig([]string{id})
+	if err != nil {
+		return fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	if err := checkExpected(configs, "", opts); err != nil {
+		return err
+	}
+
+	oldConfig := emptyConfig
+	if len(configs) == 1 {
+		oldConfig = configs[0].Body
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(oldConfig, &decoded); err != nil {
+		return fmt.Errorf("Error decoding config: %v", err)
+	}
+
+	patched, err := applyPatch(decoded, patch)
+	if err != nil {
+		return err
+	}
+	if _, ok := patched.(map[string]interface{}); !ok {
+		return &PatchError{Index: -1, Message: "Top level config should be a JSON object"}
+	}
+	b, err := json.Marshal(patched)
+	if err != nil {
+		return fmt.Errorf("Error encoding new config: %v", err)
+	}
+	revision, err := nextRevision(id, configs)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(&ChangeSet{
+		Id:        id,
+		Body:      b,
+		Timestamp: time.Now(),
+		UserMech:  userMech,
+		UserId:    userId,
+		Message:   message,
+		ChangeId:  changeId,
+		OldConfig: oldConfig,
+		Revision:  revision,
+		Patch:     patch,
+	}, opts)
 }
 
 // CreateOrUpdateConfig will create or update the config for id at the specified path.
 // Message should be a description of the change.
 // Data should be the JSON data.
 // userMech identifies the authentication mechanism of the scope from which this change was applied
-func CreateOrUpdateConfig(changeId, id, path, userMech, userId, message string, data []byte) error {
+func CreateOrUpdateConfig(changeId, id, path, userMech, userId, message string, data []byte, opts *WriteOptions) error {
 	var newNode interface{}
 	err := json.Unmarshal(data, &newNode)
 	if err != nil {
@@ -289,6 +1008,32 @@ func CreateOrUpdateConfig(changeId, id, path, userMech, userId, message string,
 		return fmt.Errorf("Error getting config from DAO: %v", err)
 	}
 
+	cs, err := updateChangeSet(configs, changeId, id, path, userMech, userId, message, data, newNode, opts)
+	if err != nil {
+		return err
+	}
+	if opts == nil || opts.ExpiresAt.IsZero() {
+		return saveConfig(cs, opts)
+	}
+
+	if !opts.ExpiresAt.After(cs.Timestamp) {
+		return ErrExpiryInPast
+	}
+	cs.ExpiresAt = opts.ExpiresAt
+	if err := saveConfig(cs, opts); err != nil {
+		return err
+	}
+	return saveOverride(cs, opts)
+}
+
+// updateChangeSet builds the change which writes data, decoded as newNode, to id at path,
+// where configs is the config for id as it currently stands
+func updateChangeSet(configs []*ChangeSet, changeId, id, path, userMech, userId, message string, data []byte, newNode interface{}, opts *WriteOptions) (*ChangeSet, error) {
+	if err := checkExpected(configs, path, opts); err != nil {
+		return nil, err
+	}
+
+	var err error
 	oldConfig := make([]byte, 0)
 	if len(configs) == 1 {
 		oldConfig, err = readConfigAtPath(configs[0].Body, path)
@@ -296,36 +1041,68 @@ func CreateOrUpdateConfig(changeId, id, path, userMech, userId, message string,
 		if err == ErrPathNotFound {
 			oldConfig = make([]byte, 0)
 		} else if err != nil {
-			return fmt.Errorf("Error getting config at path %s : %v", path, err)
+			return nil, fmt.Errorf("Error getting config at path %s : %v", path, err)
+		}
+	}
+
+	var payload, merged []byte
+	if opts != nil && opts.Merge {
+		var current interface{}
+		if len(oldConfig) > 0 {
+			if err := json.Unmarshal(oldConfig, &current); err != nil {
+				return nil, fmt.Errorf("Error decoding config: %v", err)
+			}
+		}
+		newNode = applyMergePatch(current, newNode)
+		if merged, err = json.Marshal(newNode); err != nil {
+			return nil, fmt.Errorf("Error encoding new config: %v", err)
 		}
+		payload, data = data, merged
 	}
 
+	body := data
 	if path == "" {
 		// If we are updating at the top level, it should be an object at the top level
-		var target map[string]interface{}
-		err = json.Unmarshal(data, &target)
-		if err != nil {
-			return fmt.Errorf("Top level config should be a JSON object")
+		if _, ok := newNode.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("Top level config should be a JSON object")
 		}
-
-		return DefaultRepository.UpdateConfig(&ChangeSet{
-			Id:        id,
-			Body:      data,
-			Timestamp: time.Now(),
-			UserMech:  userMech,
-			UserId:    userId,
-			Message:   message,
-			ChangeId:  changeId,
-			Path:      path,
-			OldConfig: oldConfig,
-		})
+	} else {
+		var current []byte
+		if len(configs) == 1 {
+			current = configs[0].Body
+		}
+		if body, err = setConfigAtPath(current, path, newNode); err != nil {
+			return nil, err
+		}
+	}
+	revision, err := nextRevision(id, configs)
+	if err != nil {
+		return nil, err
 	}
 
+	return &ChangeSet{
+		Id:         id,
+		Body:       body,
+		Timestamp:  time.Now(),
+		UserMech:   userMech,
+		UserId:     userId,
+		Message:    message,
+		ChangeId:   changeId,
+		Path:       path,
+		OldConfig:  oldConfig,
+		Revision:   revision,
+		MergePatch: payload,
+		NewConfig:  merged,
+	}, nil
+}
+
+// setConfigAtPath returns body with the node at the non-empty path replaced by newNode
+func setConfigAtPath(body []byte, path string, newNode interface{}) ([]byte, error) {
 	decoded := make(map[string]interface{})
-	if len(configs) == 1 && len(configs[0].Body) > 0 {
-		err := json.Unmarshal(configs[0].Body, &decoded)
+	if len(body) > 0 {
+		err := json.Unmarshal(body, &decoded)
 		if err != nil {
-			return fmt.Errorf("Error parsing JSON: %v", err)
+			return nil, fmt.Errorf("Error parsing JSON: %v", err)
 		}
 	}
 
@@ -341,32 +1118,34 @@ func CreateOrUpdateConfig(changeId, id, path, userMech, userId, message string,
 		}
 
 		node, ok := parent[part]
-		if !ok {
+		if !ok || node == nil {
 			// Make new node
 			parent[part] = make(map[string]interface{})
 			parent = parent[part].(map[string]interface{})
 			continue
 		}
 
-		parent = node.(map[string]interface{})
+		parent, ok = node.(map[string]interface{})
+		if !ok {
+			return nil, &ErrPathConflict{Path: strings.Join(parts[:i+1], "/")}
+		}
 	}
 
 	b, err := json.Marshal(decoded)
 	if err != nil {
-		return fmt.Errorf("Error encoding new config: %v", err)
+		return nil, fmt.Errorf("Error encoding new config: %v", err)
 	}
+	return b, nil
+}
 
-	return DefaultRepository.UpdateConfig(&ChangeSet{
-		Id:        id,
-		Body:      b,
-		Timestamp: time.Now(),
-		UserMech:  userMech,
-		UserId:    userId,
-		Message:   message,
-		ChangeId:  changeId,
-		Path:      path,
-		OldConfig: oldConfig,
-	})
+// checkSchemaRegistry makes sure that writes to the schema registry leave it
+// containing only valid schemas
+func checkSchemaRegistry(id string, body []byte) error {
+	if id != SchemaId {
+		return nil
+	}
+	_, err := parseSchemaRegistry(body)
+	return err
 }
 
 func lockPath(id string) string {
@@ -257,7 +257,7 @@ func (s *DomainSuite) TestUpdateConfig() {
 			On("NewLock", lockPath(tc.key), gozk.WorldACL(gozk.PermAll)).
 			Return(&mockLock{})
 
-		err := CreateOrUpdateConfig("foo", tc.key, tc.path, "Test Message", "h2", "dave", []byte(tc.newValue))
+		err := CreateOrUpdateConfig("foo", tc.key, tc.path, "Test Message", "h2", "dave", []byte(tc.newValue), nil)
 		if !tc.shouldError {
 			s.NoError(err)
 			continue
@@ -487,7 +487,11 @@ func (s *DomainSuite) TestDeleteConfig() {
 
 		DefaultRepository = testRepo
 
-		err := DeleteConfig("foo", tc.key, tc.path, "h2", "dave", "Test Message")
+		s.zk.
+			On("NewLock", lockPath(tc.key), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+
+		err := DeleteConfig("foo", tc.key, tc.path, "h2", "dave", "Test Message", nil)
 		s.NoError(err)
 
 		updated := testRepo.data["a"]
@@ -535,7 +539,7 @@ func (s *DomainSuite) TestUpdateConfigConcurrently() {
 		for _, path := range paths {
 			go func(path string) {
 				msg := fmt.Sprintf("Setting %s to %d", path, i)
-				err := CreateOrUpdateConfig(fmt.Sprintf("%s:%d", path, i), id, path, userMech, userId, msg, []byte(fmt.Sprintf("%d", i)))
+				err := CreateOrUpdateConfig(fmt.Sprintf("%s:%d", path, i), id, path, userMech, userId, msg, []byte(fmt.Sprintf("%d", i)), nil)
 				s.NoError(err)
 
 				wg.Done()
@@ -573,3 +577,493 @@ func (l *mockLock) Unlock() error {
 
 func (l *mockLock) SetTTL(x time.Duration)     {}
 func (l *mockLock) SetTimeout(x time.Duration) {}
+
+func (s *DomainSuite) TestReadConfigAtRevision() {
+	id := "a"
+	start := time.Now().Add(-time.Hour)
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{
+				Id:        id,
+				Body:      []byte(`{"foo":{"bar":1}}`),
+				Timestamp: start,
+				Revision:  1,
+			},
+		},
+	}
+
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	s.NoError(CreateOrUpdateConfig("c2", id, "foo/bar", "h2", "dave", "Second", []byte(`2`), nil))
+	s.NoError(CreateOrUpdateConfig("c3", id, "foo/baz", "h2", "dave", "Third", []byte(`3`), nil))
+	s.NoError(DeleteConfig("c4", id, "foo/bar", "h2", "dave", "Fourth", nil))
+
+	testCases := []struct {
+		revision int64
+		changeId string
+		expected string
+	}{
+		{1, "", `{"bar":1}`},
+		{2, "c2", `{"bar":2}`},
+		{3, "c3", `{"bar":2,"baz":3}`},
+		{4, "c4", `{"baz":3}`},
+	}
+	for _, tc := range testCases {
+		b, cs, err := ReadConfigAtRevision(id, "foo", tc.revision)
+		s.NoError(err)
+		s.Equal(tc.revision, cs.Revision)
+		s.Equal(tc.changeId, cs.ChangeId)
+		eq, err := compareJson([]byte(tc.expected), b)
+		s.NoError(err)
+		s.True(eq, "Config at revision %v incorrect: %s", tc.revision, b)
+	}
+
+	_, _, err := ReadConfigAtRevision(id, "", 5)
+	s.Equal(ErrRevisionNotFound, err)
+
+	// By time
+	b, cs, err := ReadConfigAt(id, "foo", start.Add(time.Minute))
+	s.NoError(err)
+	s.Equal(int64(1), cs.Revision)
+	s.Equal(`{"bar":1}`, string(b))
+
+	_, cs, err = ReadConfigAt(id, "", time.Now())
+	s.NoError(err)
+	s.Equal(int64(4), cs.Revision)
+
+	_, _, err = ReadConfigAt(id, "", start.Add(-time.Minute))
+	s.Equal(ErrRevisionNotFound, err)
+}
+
+func (s *DomainSuite) TestRollbackConfig() {
+	id := "a"
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	s.NoError(CreateOrUpdateConfig("c1", id, "", "h2", "dave", "First", []byte(`{"foo":1}`), nil))
+	s.NoError(CreateOrUpdateConfig("c2", id, "bar", "h2", "dave", "Second", []byte(`2`), nil))
+	s.NoError(CreateOrUpdateConfig("c3", id, "foo", "h2", "dave", "Third", []byte(`3`), nil))
+
+	testCases := []struct {
+		target       *RollbackTarget
+		rolledBackTo int64
+		expected     string
+	}{
+		{&RollbackTarget{Revision: 1}, 1, `{"foo":1}`},
+		{&RollbackTarget{ChangeId: "c2"}, 2, `{"foo":1,"bar":2}`},
+		// Undoing the rollback above
+		{&RollbackTarget{Undo: 1}, 4, `{"foo":1}`},
+	}
+	for i, tc := range testCases {
+		cs, err := RollbackConfig("r", id, "h2", "dave", "", tc.target, nil)
+		s.NoError(err, "Unexpected error for testcase %v", i)
+		s.True(cs.RolledBack)
+		s.Equal(tc.rolledBackTo, cs.RolledBackTo)
+		s.Equal(int64(4+i), cs.Revision)
+		s.Equal(fmt.Sprintf("Rollback to revision %v", tc.rolledBackTo), cs.Message)
+		eq, err := compareJson([]byte(tc.expected), testRepo.data[id].Body)
+		s.NoError(err)
+		s.True(eq, "Config incorrect for testcase %v: %s", i, testRepo.data[id].Body)
+	}
+
+	for _, target := range []*RollbackTarget{{Revision: 10}, {ChangeId: "missing"}, {Undo: 10}, {Undo: -1}} {
+		_, err := RollbackConfig("r", id, "h2", "dave", "", target, nil)
+		s.Equal(ErrRevisionNotFound, err)
+	}
+
+	s.zk.
+		On("NewLock", lockPath("missing"), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+	_, err := RollbackConfig("r", "missing", "h2", "dave", "", &RollbackTarget{Revision: 1}, nil)
+	s.Equal(ErrIdNotFound, err)
+}
+
+func (s *DomainSuite) TestRollbackConfigBeforeRevisions() {
+	id := "a"
+	// Seeded without UpdateConfig, as config last written before revisions were kept
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{"foo":1}`), ChangeId: "c0"},
+		},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	s.NoError(CreateOrUpdateConfig("c1", id, "foo", "h2", "dave", "First", []byte(`2`), nil))
+	s.Equal(int64(1), testRepo.data[id].Revision)
+
+	for i, target := range []*RollbackTarget{{Undo: 1}, {Revision: 0}, {ChangeId: "c0"}} {
+		cs, err := RollbackConfig(fmt.Sprintf("r%v", i), id, "h2", "dave", "", target, nil)
+		s.NoError(err, "Unexpected error for testcase %v", i)
+		// Recorded as a rollback, though to revision 0
+		s.Equal(cs.ChangeId, testRepo.data[id].ChangeId)
+		s.True(testRepo.data[id].RolledBack)
+		s.Equal(int64(0), testRepo.data[id].RolledBackTo)
+		eq, err := compareJson([]byte(`{"foo":1}`), testRepo.data[id].Body)
+		s.NoError(err)
+		s.True(eq, "Config incorrect for testcase %v: %s", i, testRepo.data[id].Body)
+	}
+}
+
+func (s *DomainSuite) TestCompileConfigAsOf() {
+	then := time.Now().Add(-time.Hour)
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			"a": &ChangeSet{Id: "a", Body: []byte(`{"foo":{"bar":1,"baz":1}}`), Timestamp: then.Add(-time.Minute), ChangeId: "a1", Revision: 1},
+			"b": &ChangeSet{Id: "b", Body: []byte(`{"foo":{"bar":2}}`), Timestamp: then.Add(-time.Minute), ChangeId: "b1", Revision: 1},
+			"c": &ChangeSet{Id: "c", Body: []byte(`{"foo":{"baz":3}}`), Timestamp: then.Add(time.Minute), ChangeId: "c1", Revision: 1},
+		},
+	}
+	s.NoError(DefaultRepository.UpdateConfig(&ChangeSet{
+		Id: "b", Body: []byte(`{"foo":{"bar":4}}`), Timestamp: then.Add(time.Minute), ChangeId: "b2", Revision: 2,
+	}))
+
+	compiled, configs, err := CompileConfigWithOptions([]string{"a", "b", "c"}, "foo", &CompileOptions{AsOf: then})
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"bar":2,"baz":1}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+	s.Len(configs, 2)
+	s.Equal("a1", configs[0].ChangeId)
+	s.Equal("b1", configs[1].ChangeId)
+
+	explained, _, err := ExplainConfigWithOptions([]string{"a", "b", "c"}, "foo", &CompileOptions{AsOf: then})
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"bar":"b","baz":"a"}`), explained)
+	s.NoError(err)
+	s.True(eq, "Explained config incorrect: %s", explained)
+
+	compiled, configs, err = CompileConfigWithOptions([]string{"a", "b", "c"}, "foo", nil)
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"bar":4,"baz":3}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+	s.Len(configs, 3)
+	s.Equal("b2", configs[1].ChangeId)
+
+	// Existed at the time, but its history does not go back that far
+	s.NoError(DefaultRepository.UpdateConfig(&ChangeSet{
+		Id: "d", Body: []byte(`{"foo":{"bar":5}}`), Timestamp: then.Add(time.Minute), ChangeId: "d3", Revision: 3,
+	}))
+	_, _, err = CompileConfigWithOptions([]string{"a", "b", "c", "d"}, "foo", &CompileOptions{AsOf: then})
+	s.Equal(ErrRevisionNotFound, err)
+}
+
+func (s *DomainSuite) TestWriteExpectedVersion() {
+	id := "a"
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{
+				Id:        id,
+				Body:      []byte(`{"foo":{"bar":1},"baz":2}`),
+				Timestamp: time.Now(),
+				Revision:  3,
+			},
+		},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	current, _, err := ReadConfig(id, "foo")
+	s.NoError(err)
+	hash := ConfigHash(current)
+
+	// Stale expectations are rejected
+	err = CreateOrUpdateConfig("c", id, "foo", "h2", "dave", "Test Message", []byte(`1`), &WriteOptions{ExpectedRevision: 2})
+	s.Equal(ErrConfigChanged, err)
+	err = CreateOrUpdateConfig("c", id, "foo", "h2", "dave", "Test Message", []byte(`1`), &WriteOptions{ExpectedHash: "abc"})
+	s.Equal(ErrConfigChanged, err)
+	err = DeleteConfig("c", id, "foo", "h2", "dave", "Test Message", &WriteOptions{ExpectedRevision: 4})
+	s.Equal(ErrConfigChanged, err)
+	s.Equal(int64(3), testRepo.data[id].Revision)
+
+	// Other paths can change without affecting the hash
+	err = CreateOrUpdateConfig("c", id, "baz", "h2", "dave", "Test Message", []byte(`3`), &WriteOptions{ExpectedRevision: 3})
+	s.NoError(err)
+	err = CreateOrUpdateConfig("c", id, "foo", "h2", "dave", "Test Message", []byte(`{"bar":2}`), &WriteOptions{ExpectedHash: hash})
+	s.NoError(err)
+
+	// The hash now differs
+	err = DeleteConfig("c", id, "foo", "h2", "dave", "Test Message", &WriteOptions{ExpectedHash: hash})
+	s.Equal(ErrConfigChanged, err)
+	err = DeleteConfig("c", id, "foo", "h2", "dave", "Test Message", &WriteOptions{ExpectedRevision: 5})
+	s.NoError(err)
+}
+
+func (s *DomainSuite) TestUpdateConfigMerge() {
+	id := "a"
+	initial := `{"hailo":{"service":{"memcache":{"hosts":["a"],"timeout":"1s"},"zk":"x"}}}`
+
+	testCases := []struct {
+		path     string
+		value    string
+		expected string
+		newNode  string
+	}{
+		// Only the given keys change
+		{"hailo/service/memcache", `{"timeout":"2s"}`,
+			`{"hailo":{"service":{"memcache":{"hosts":["a"],"timeout":"2s"},"zk":"x"}}}`, `{"hosts":["a"],"timeout":"2s"}`},
+		// Null deletes, arrays are replaced
+		{"hailo/service", `{"memcache":{"hosts":["b"],"timeout":null},"zk":null}`,
+			`{"hailo":{"service":{"memcache":{"hosts":["b"]}}}}`, `{"memcache":{"hosts":["b"]}}`},
+		// An object replaces a value, and a new path is created
+		{"", `{"hailo":{"service":{"zk":{"hosts":["c"]}}},"foo":{"bar":1}}`,
+			`{"hailo":{"service":{"memcache":{"hosts":["a"],"timeout":"1s"},"zk":{"hosts":["c"]}}},"foo":{"bar":1}}`, ``},
+		{"foo/bar", `{"baz":1}`,
+			`{"hailo":{"service":{"memcache":{"hosts":["a"],"timeout":"1s"},"zk":"x"}},"foo":{"bar":{"baz":1}}}`, `{"baz":1}`},
+		// Deletion markers are kept as they are
+		{"hailo/service", `{"memcache":{"$delete":true}}`,
+			`{"hailo":{"service":{"memcache":{"$delete":true},"zk":"x"}}}`, `{"memcache":{"$delete":true},"zk":"x"}`},
+	}
+
+	for i, tc := range testCases {
+		testRepo := &memoryRepository{
+			data: map[string]*ChangeSet{
+				id: &ChangeSet{Id: id, Body: []byte(initial), Timestamp: time.Now()},
+			},
+		}
+		DefaultRepository = testRepo
+
+		s.zk.
+			On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+
+		err := CreateOrUpdateConfig("foo", id, tc.path, "h2", "dave", "Test Message", []byte(tc.value), &WriteOptions{Merge: true})
+		s.NoError(err, "Unexpected error for testcase %v", i)
+
+		cs := testRepo.data[id]
+		eq, err := compareJson([]byte(tc.expected), cs.Body)
+		s.NoError(err)
+		s.True(eq, "Merged config incorrect for testcase %v: %s", i, cs.Body)
+		s.Equal(tc.value, string(cs.MergePatch))
+		if tc.newNode != "" {
+			eq, err = compareJson([]byte(tc.newNode), cs.NewConfig)
+			s.NoError(err)
+			s.True(eq, "New config incorrect for testcase %v: %s", i, cs.NewConfig)
+		}
+	}
+}
+
+func (s *DomainSuite) TestBatchUpdateConfig() {
+	base, region := "H2:BASE", "H2:REGION:eu-west-1"
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			SchemaId: &ChangeSet{
+				Id:        SchemaId,
+				Body:      []byte(schemaRegistry),
+				Timestamp: time.Now(),
+			},
+			base: &ChangeSet{
+				Id:        base,
+				Body:      []byte(`{}`),
+				Timestamp: time.Now(),
+				Revision:  1,
+			},
+		},
+	}
+	DefaultRepository = testRepo
+
+	for _, id := range []string{base, region} {
+		s.zk.
+			On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+	}
+
+	compiled := &WriteOptions{ValidateCompiled: true}
+	regionUpdate := &BatchUpdate{ChangeId: "c1", Id: region, Path: "hailo/service/zookeeper/recvTimeout", Config: []byte(`"200ms"`), Opts: compiled}
+
+	// On its own the region change fails compiled validation, as the base has no hosts
+	_, err := BatchUpdateConfig("batch1", "h2", "dave", "Test Message", []*BatchUpdate{regionUpdate})
+	berr, ok := err.(*BatchError)
+	s.True(ok, "Expected batch error, got %v", err)
+	if ok {
+		s.Equal(0, berr.Index)
+		_, ok = berr.Err.(*ValidationError)
+		s.True(ok, "Expected validation error, got %v", berr.Err)
+	}
+
+	// A stale update fails the whole batch
+	_, err = BatchUpdateConfig("batch2", "h2", "dave", "Test Message", []*BatchUpdate{
+		regionUpdate,
+		{ChangeId: "c2", Id: base, Path: "hailo/service/zookeeper/hosts", Config: []byte(`["zk01"]`), Opts: &WriteOptions{ExpectedRevision: 2}},
+	})
+	berr, ok = err.(*BatchError)
+	s.True(ok, "Expected batch error, got %v", err)
+	if ok {
+		s.Equal(1, berr.Index)
+		s.Equal(ErrConfigChanged, berr.Err)
+	}
+	_, ok = testRepo.data[region]
+	s.False(ok, "Region config was saved by a failed batch")
+	s.Equal(int64(1), testRepo.data[base].Revision)
+
+	// Together they validate, and later updates to an ID build on earlier ones
+	changes, err := BatchUpdateConfig("batch3", "h2", "dave", "Test Message", []*BatchUpdate{
+		regionUpdate,
+		{ChangeId: "c2", Id: base, Path: "hailo/service/zookeeper/hosts", Config: []byte(`["zk01"]`), Opts: &WriteOptions{ExpectedRevision: 1}},
+		{ChangeId: "c3", Id: base, Path: "hailo/service/zookeeper", Config: []byte(`{"recvTimeout":"100ms"}`), Opts: &WriteOptions{Merge: true}},
+	})
+	s.NoError(err)
+	s.Len(changes, 3)
+	for i, cs := range changes {
+		s.Equal("batch3", cs.BatchId)
+		s.Equal(fmt.Sprintf("c%d", i+1), cs.ChangeId)
+	}
+
+	s.Equal(int64(3), testRepo.data[base].Revision)
+	s.Equal("c3", testRepo.data[base].ChangeId)
+	eq, err := compareJson([]byte(`{"hailo":{"service":{"zookeeper":{"hosts":["zk01"],"recvTimeout":"100ms"}}}}`), testRepo.data[base].Body)
+	s.NoError(err)
+	s.True(eq, "Base config incorrect: %s", testRepo.data[base].Body)
+	eq, err = compareJson([]byte(`{"hailo":{"service":{"zookeeper":{"recvTimeout":"200ms"}}}}`), testRepo.data[region].Body)
+	s.NoError(err)
+	s.True(eq, "Region config incorrect: %s", testRepo.data[region].Body)
+}
+
+func (s *DomainSuite) TestListIds() {
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			"H2:BASE":                    &ChangeSet{Id: "H2:BASE", Body: []byte(`{}`), Revision: 4},
+			"H2:BASE:com.HailoOSS.foo":   &ChangeSet{Id: "H2:BASE:com.HailoOSS.foo", Body: []byte(`{}`), Message: "foo"},
+			"H2:BASE:com.HailoOSS.bar":   &ChangeSet{Id: "H2:BASE:com.HailoOSS.bar", Body: []byte(`{}`)},
+			"H2:REGION:eu-west-1":        &ChangeSet{Id: "H2:REGION:eu-west-1", Body: []byte(`{}`)},
+			"CITY:LON":                   &ChangeSet{Id: "CITY:LON", Body: []byte(`{}`)},
+			"H2:BASE:com.HailoOSS.zookd": &ChangeSet{Id: "H2:BASE:com.HailoOSS.zookd", Body: []byte(`{}`)},
+		},
+	}
+
+	css, cursor, err := ListIds("", "", 10)
+	s.NoError(err)
+	s.Len(css, 6)
+	s.Equal("", cursor)
+	s.Equal("CITY:LON", css[0].Id)
+	s.Equal(int64(4), css[1].Revision)
+	s.Empty(css[1].Body, "Summaries should not include config")
+
+	// Paginate through a prefix
+	css, cursor, err = ListIds("H2:BASE:", "", 2)
+	s.NoError(err)
+	s.Len(css, 2)
+	s.Equal("H2:BASE:com.HailoOSS.bar", css[0].Id)
+	s.Equal("H2:BASE:com.HailoOSS.foo", css[1].Id)
+	s.Equal("foo", css[1].Message)
+	s.Equal("H2:BASE:com.HailoOSS.foo", cursor)
+
+	css, cursor, err = ListIds("H2:BASE:", cursor, 2)
+	s.NoError(err)
+	s.Len(css, 1)
+	s.Equal("H2:BASE:com.HailoOSS.zookd", css[0].Id)
+	s.Equal("", cursor)
+
+	for _, count := range []int{0, -1} {
+		_, _, err = ListIds("H2:BASE:", "", count)
+		s.Error(err)
+	}
+}
+
+func (s *DomainSuite) TestDeleteId() {
+	id := "a"
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{
+				Id:        id,
+				Body:      []byte(`{"foo":1}`),
+				Timestamp: time.Now().Add(-time.Hour),
+				Revision:  2,
+			},
+		},
+	}
+	DefaultRepository = testRepo
+
+	for _, lockId := range []string{id, "b"} {
+		s.zk.
+			On("NewLock", lockPath(lockId), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+	}
+
+	// Nothing to undelete while it exists
+	_, err := UndeleteId("c", id, "h2", "dave", "", nil)
+	s.Equal(ErrIdExists, err)
+
+	cs, err := DeleteId("c1", id, "h2", "dave", "Test Message", nil)
+	s.NoError(err)
+	s.Equal(int64(3), cs.Revision)
+	s.True(cs.Deleted)
+
+	// It is gone, rather than empty
+	_, _, err = ReadConfig(id, "")
+	s.Equal(ErrIdNotFound, err)
+	css, _, err := ListIds("", "", 10)
+	s.NoError(err)
+	s.Len(css, 0)
+	_, _, err = ReadConfigAt(id, "", time.Now())
+	s.Equal(ErrIdNotFound, err)
+	_, err = DeleteId("c", id, "h2", "dave", "Test Message", nil)
+	s.Equal(ErrIdNotFound, err)
+
+	// Earlier revisions can still be read
+	config, _, err := ReadConfigAtRevision(id, "", 2)
+	s.NoError(err)
+	s.Equal(`{"foo":1}`, string(config))
+
+	cs, err = UndeleteId("c2", id, "h2", "dave", "", nil)
+	s.NoError(err)
+	s.Equal(int64(4), cs.Revision)
+	s.True(cs.RolledBack)
+	s.Equal(int64(2), cs.RolledBackTo)
+	config, _, err = ReadConfig(id, "")
+	s.NoError(err)
+	s.Equal(`{"foo":1}`, string(config))
+
+	// Recreating a deleted ID carries on its revisions
+	_, err = DeleteId("c3", id, "h2", "dave", "Test Message", nil)
+	s.NoError(err)
+	_, err = UndeleteId("c", "b", "h2", "dave", "", nil)
+	s.Equal(ErrIdNotFound, err)
+	err = CreateOrUpdateConfig("c4", id, "", "h2", "dave", "Test Message", []byte(`{"bar":2}`), nil)
+	s.NoError(err)
+	s.Equal(int64(6), testRepo.data[id].Revision)
+}
+
+func (s *DomainSuite) TestChangesSince() {
+	id := "H2:BASE"
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{}`), Revision: 1, Timestamp: time.Now().Add(-time.Hour)},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	start := time.Now()
+	for i := 0; i < changeLogPageSize+5; i++ {
+		err := CreateOrUpdateConfig(fmt.Sprintf("c%d", i), id, "foo", "h2", "dave", "Foo", []byte(fmt.Sprintf("%d", i)), nil)
+		s.NoError(err)
+	}
+
+	// Every change since, across pages, oldest first
+	css, err := ChangesSince(start)
+	s.NoError(err)
+	s.Len(css, changeLogPageSize+5)
+	s.Equal("c0", css[0].ChangeId)
+	s.Equal(fmt.Sprintf("c%d", changeLogPageSize+4), css[len(css)-1].ChangeId)
+
+	css, err = ChangesSince(time.Now())
+	s.NoError(err)
+	s.Len(css, 0)
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	pathpkg "path"
+	"time"
+)
+
+const (
+	// FreezeId is the config ID under which windows of time when config must not change
+	// are defined, eg:
+	// {"windows":[{"ids":["CITY:*"],"start":"2026-12-24T00:00:00Z","end":"2026-12-27T00:00:00Z","reason":"Christmas"}]}
+	// It can always be changed, so that freezes can be lifted.
+	FreezeId = "FREEZE"
+)
+
+var (
+	ErrBreakGlassMessage = errors.New("Breaking glass needs a message saying why")
+)
+
+// FreezeWindow is a period during which changes are blocked unless they break glass
+type FreezeWindow struct {
+	// Ids are patterns, where * matches any characters, of the IDs frozen; if there are
+	// none, every ID is
+	Ids    []string  `json:"ids,omitempty"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason"`
+}
+
+// covers returns true if the window freezes id at time t
+func (w *FreezeWindow) covers(id string, t time.Time) bool {
+	if t.Before(w.Start) || !t.Before(w.End) {
+		return false
+	}
+	if len(w.Ids) == 0 {
+		return true
+	}
+	for _, pattern := range w.Ids {
+		if ok, _ := pathpkg.Match(pattern, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// freezeDefinition is the body stored under FreezeId
+type freezeDefinition struct {
+	Windows []*FreezeWindow `json:"windows"`
+}
+
+// FrozenError is returned when a change is blocked by a freeze window
+type FrozenError struct {
+	Id     string
+	Window *FreezeWindow
+}
+
+func (e *FrozenError) Error() string {
+	return fmt.Sprintf("Config for %q is frozen until %s: %s", e.Id, e.Window.End.Format(time.RFC3339), e.Window.Reason)
+}
+
+// parseFreezeWindows returns the windows of a freeze definition
+func parseFreezeWindows(body []byte) ([]*FreezeWindow, error) {
+	def := &freezeDefinition{}
+	if err := json.Unmarshal(body, def); err != nil {
+		return nil, &ValidationError{Violations: []*Violation{{Message: fmt.Sprintf("Freeze windows are not valid: %v", err)}}}
+	}
+
+	var violations []*Violation
+	for i, w := range def.Windows {
+		if !w.End.After(w.Start) {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("windows/%d", i),
+				Message: "Window should end after it starts",
+			})
+		}
+		if w.Reason == "" {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("windows/%d/reason", i),
+				Message: "Window should give a reason",
+			})
+		}
+		for j, id := range w.Ids {
+			if _, err := pathpkg.Match(id, ""); id == "" || err != nil {
+				violations = append(violations, &Violation{
+					Path:    fmt.Sprintf("windows/%d/ids/%d", i, j),
+					Message: fmt.Sprintf("Invalid ID pattern %q", id),
+				})
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return def.Windows, nil
+}
+
+// checkFreezeWindows makes sure a change to the freeze windows is valid
+func checkFreezeWindows(id string, body []byte) error {
+	if id != FreezeId {
+		return nil
+	}
+	_, err := parseFreezeWindows(body)
+	return err
+}
+
+// ReadFreezeWindows returns the stored freeze windows, of which there are none until
+// some are stored
+func ReadFreezeWindows() ([]*FreezeWindow, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{FreezeId})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting freeze windows from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return nil, nil
+	}
+	return parseFreezeWindows(configs[0].Body)
+}
+
+// checkFreeze returns a FrozenError if the change falls within a freeze window for its
+// ID, unless it breaks glass, which is recorded against the change and needs a message
+func checkFreeze(cs *ChangeSet, opts *WriteOptions) error {
+	if opts != nil && opts.BreakGlass {
+		message := cs.Message
+		if opts.breakGlassMessage != "" {
+			message = opts.breakGlassMessage
+		}
+		if message == "" {
+			return ErrBreakGlassMessage
+		}
+		cs.BreakGlass = true
+		return nil
+	}
+	if cs.Id == FreezeId {
+		return nil
+	}
+
+	windows, err := ReadFreezeWindows()
+	if err != nil {
+		return err
+	}
+	for _, w := range windows {
+		if w.covers(cs.Id, cs.Timestamp) {
+			return &FrozenError{Id: cs.Id, Window: w}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestFreeze() {
+	now := time.Now().UTC()
+	windows := fmt.Sprintf(`{"windows":[
+		{"ids":["CITY:*"],"start":%q,"end":%q,"reason":"New year"},
+		{"start":%q,"end":%q,"reason":"Last year"}
+	]}`,
+		now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339),
+		now.Add(-48*time.Hour).Format(time.RFC3339), now.Add(-24*time.Hour).Format(time.RFC3339))
+
+	repo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			FreezeId: &ChangeSet{Id: FreezeId, Body: []byte(windows), Timestamp: now},
+		},
+	}
+	DefaultRepository = repo
+	for _, id := range []string{"CITY:LON", "H2:BASE", FreezeId} {
+		s.zk.
+			On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+	}
+
+	// Only IDs within a current window are frozen
+	s.NoError(CreateOrUpdateConfig("c1", "H2:BASE", "", "h2", "dave", "Base", []byte(`{"foo":1}`), nil))
+	err := CreateOrUpdateConfig("c2", "CITY:LON", "", "h2", "dave", "London", []byte(`{"foo":1}`), nil)
+	ferr, ok := err.(*FrozenError)
+	s.True(ok, "Expected frozen error, got %v", err)
+	if ok {
+		s.Equal("New year", ferr.Window.Reason)
+	}
+	_, err = BatchUpdateConfig("b1", "h2", "dave", "Both", []*BatchUpdate{
+		{ChangeId: "c3", Id: "H2:BASE", Config: []byte(`{"foo":2}`)},
+		{ChangeId: "c4", Id: "CITY:LON", Config: []byte(`{"foo":2}`)},
+	})
+	berr, ok := err.(*BatchError)
+	s.True(ok, "Expected batch error, got %v", err)
+	if ok {
+		s.Equal(1, berr.Index)
+		_, ok = berr.Err.(*FrozenError)
+		s.True(ok, "Expected frozen error, got %v", berr.Err)
+	}
+
+	// Breaking glass needs a message, and is recorded
+	err = CreateOrUpdateConfig("c5", "CITY:LON", "", "h2", "dave", "", []byte(`{"foo":1}`), &WriteOptions{BreakGlass: true})
+	s.Equal(ErrBreakGlassMessage, err)
+	s.NoError(CreateOrUpdateConfig("c6", "CITY:LON", "", "h2", "dave", "Outage", []byte(`{"foo":1}`), &WriteOptions{BreakGlass: true}))
+	_, cs, err := ReadConfig("CITY:LON", "")
+	s.NoError(err)
+	s.True(cs.BreakGlass)
+
+	// Freezes can always be lifted
+	s.NoError(CreateOrUpdateConfig("c7", FreezeId, "", "h2", "dave", "Lift", []byte(`{"windows":[]}`), nil))
+	s.NoError(CreateOrUpdateConfig("c8", "CITY:LON", "", "h2", "dave", "London", []byte(`{"foo":2}`), nil))
+	_, cs, err = ReadConfig("CITY:LON", "")
+	s.NoError(err)
+	s.False(cs.BreakGlass)
+
+	for _, body := range []string{
+		`{"windows":[{"start":"2026-01-02T00:00:00Z","end":"2026-01-01T00:00:00Z","reason":"Backwards"}]}`,
+		`{"windows":[{"start":"2026-01-01T00:00:00Z","end":"2026-01-02T00:00:00Z"}]}`,
+		`{"windows":[{"ids":["["],"start":"2026-01-01T00:00:00Z","end":"2026-01-02T00:00:00Z","reason":"Bad"}]}`,
+	} {
+		_, ok := checkFreezeWindows(FreezeId, []byte(body)).(*ValidationError)
+		s.True(ok, "Expected invalid freeze windows %s to fail", body)
+	}
+}
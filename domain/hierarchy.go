@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// HierarchyId is the config ID under which the hierarchy of IDs which services
+	// compile is defined, eg: {"layers":["H2:BASE","H2:BASE:{service}"]}. It is stored
+	// like any other config, so layers can be added without redeploying clients.
+	HierarchyId = "HIERARCHY"
+)
+
+var (
+	// defaultHierarchy is used until one is stored under HierarchyId
+	defaultHierarchy = []string{
+		"H2:BASE",
+		"H2:BASE:{service}",
+		"H2:REGION:{region}",
+		"H2:REGION:{region}:{service}",
+	}
+
+	hierarchyVar = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+)
+
+// hierarchyDefinition is the body stored under HierarchyId
+type hierarchyDefinition struct {
+	// Layers are ID templates in the order they are merged, where {name} is replaced by
+	// the value of the variable name
+	Layers []string `json:"layers"`
+}
+
+// parseHierarchy returns the layers of a hierarchy definition
+func parseHierarchy(body []byte) ([]string, error) {
+	def := &hierarchyDefinition{}
+	if err := json.Unmarshal(body, def); err != nil {
+		return nil, &ValidationError{Violations: []*Violation{{Message: fmt.Sprintf("Hierarchy is not valid: %v", err)}}}
+	}
+	if len(def.Layers) == 0 {
+		return nil, &ValidationError{Violations: []*Violation{{Path: "layers", Message: "Hierarchy should have at least one layer"}}}
+	}
+
+	var violations []*Violation
+	for i, layer := range def.Layers {
+		if layer == "" || strings.ContainsAny(hierarchyVar.ReplaceAllString(layer, ""), "{}") {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("layers/%d", i),
+				Message: fmt.Sprintf("Invalid layer %q", layer),
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return def.Layers, nil
+}
+
+// checkHierarchy makes sure a change to the hierarchy definition is valid
+func checkHierarchy(id string, body []byte) error {
+	if id != HierarchyId {
+		return nil
+	}
+	_, err := parseHierarchy(body)
+	return err
+}
+
+// ReadHierarchy returns the ID templates which services compile, in order
+func ReadHierarchy() ([]string, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{HierarchyId})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting hierarchy from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return defaultHierarchy, nil
+	}
+	return parseHierarchy(configs[0].Body)
+}
+
+// resolveLayer fills in the variables of an ID template, returning false if any of
+// them has no value
+func resolveLayer(layer string, vars map[string]string) (string, bool) {
+	ok := true
+	id := hierarchyVar.ReplaceAllStringFunc(layer, func(v string) string {
+		value := vars[v[1:len(v)-1]]
+		if value == "" {
+			ok = false
+		}
+		return value
+	})
+	return id, ok
+}
+
+// resolveHierarchy returns the IDs of layers, skipping any which need a variable
+// without a value
+func resolveHierarchy(layers []string, vars map[string]string) []string {
+	ids := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		if id, ok := resolveLayer(layer, vars); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ResolveIds builds the chain of IDs to compile for the given variables, eg: service
+// and region, from the stored hierarchy. Layers needing a variable which is not given
+// are left out.
+func ResolveIds(vars map[string]string) ([]string, error) {
+	layers, err := ReadHierarchy()
+	if err != nil {
+		return nil, err
+	}
+	return resolveHierarchy(layers, vars), nil
+}
+
+// matchLayer returns the variables which make the ID template layer resolve to id,
+// or false if it cannot
+func matchLayer(layer, id string) (map[string]string, bool) {
+	var names []string
+	pattern := "^"
+	last := 0
+	for _, loc := range hierarchyVar.FindAllStringSubmatchIndex(layer, -1) {
+		pattern += regexp.QuoteMeta(layer[last:loc[0]]) + "([^:]+)"
+		names = append(names, layer[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	pattern += regexp.QuoteMeta(layer[last:]) + "$"
+
+	match := regexp.MustCompile(pattern).FindStringSubmatch(id)
+	if match == nil {
+		return nil, false
+	}
+	vars := make(map[string]string, len(names))
+	for i, name := range names {
+		if v, ok := vars[name]; ok && v != match[i+1] {
+			return nil, false
+		}
+		vars[name] = match[i+1]
+	}
+	return vars, true
+}
+
+// hierarchyFor returns the IDs which compile the given ID with only its own ancestors,
+// or nil if the ID is not part of the hierarchy. For example, with the default
+// hierarchy, H2:REGION:<region> is compiled from H2:BASE and itself.
+func hierarchyFor(id string) ([]string, error) {
+	layers, err := ReadHierarchy()
+	if err != nil {
+		return nil, err
+	}
+	for i, layer := range layers {
+		if vars, ok := matchLayer(layer, id); ok {
+			return resolveHierarchy(layers[:i+1], vars), nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestResolveIds() {
+	testRepo := &memoryRepository{data: map[string]*ChangeSet{}}
+	DefaultRepository = testRepo
+
+	// The default hierarchy leaves out layers without their variables
+	ids, err := ResolveIds(map[string]string{"service": "foo", "region": "eu-west-1"})
+	s.NoError(err)
+	s.Equal([]string{"H2:BASE", "H2:BASE:foo", "H2:REGION:eu-west-1", "H2:REGION:eu-west-1:foo"}, ids)
+	ids, err = ResolveIds(map[string]string{"service": "foo"})
+	s.NoError(err)
+	s.Equal([]string{"H2:BASE", "H2:BASE:foo"}, ids)
+
+	ids, err = hierarchyFor("H2:REGION:eu-west-1")
+	s.NoError(err)
+	s.Equal([]string{"H2:BASE", "H2:REGION:eu-west-1"}, ids)
+	ids, err = hierarchyFor("CITY:LON")
+	s.NoError(err)
+	s.Empty(ids)
+
+	s.zk.
+		On("NewLock", lockPath(HierarchyId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	// Invalid hierarchies are rejected
+	for _, body := range []string{`{}`, `{"layers":"H2:BASE"}`, `{"layers":["H2:{region"]}`} {
+		err = CreateOrUpdateConfig("c", HierarchyId, "", "h2", "dave", "Test Message", []byte(body), nil)
+		_, ok := err.(*ValidationError)
+		s.True(ok, "Expected validation error for %s, got %v", body, err)
+	}
+
+	// New layers are picked up once stored
+	err = CreateOrUpdateConfig("c", HierarchyId, "", "h2", "dave", "Test Message",
+		[]byte(`{"layers":["H2:BASE","H2:ENV:{env}","H2:REGION:{region}","H2:AZ:{region}:{az}","H2:REGION:{region}:{service}"]}`), nil)
+	s.NoError(err)
+
+	ids, err = ResolveIds(map[string]string{"service": "foo", "region": "eu-west-1", "env": "live", "az": "a"})
+	s.NoError(err)
+	s.Equal([]string{"H2:BASE", "H2:ENV:live", "H2:REGION:eu-west-1", "H2:AZ:eu-west-1:a", "H2:REGION:eu-west-1:foo"}, ids)
+
+	ids, err = hierarchyFor("H2:AZ:eu-west-1:a")
+	s.NoError(err)
+	s.Equal([]string{"H2:BASE", "H2:REGION:eu-west-1", "H2:AZ:eu-west-1:a"}, ids)
+
+	testRepo.data["H2:ENV:live"] = &ChangeSet{Id: "H2:ENV:live", Body: []byte(`{"foo":1}`), Timestamp: time.Now()}
+	ids, err = ResolveIds(map[string]string{"env": "live"})
+	s.NoError(err)
+	compiled, err := CompileConfig(ids, "")
+	s.NoError(err)
+	s.Equal(`{"foo":1}`, string(compiled))
+}
@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// varPrefix marks a reference to a variable given when compiling, rather than to
+	// another path of the compiled config, eg: "${var:region}"
+	varPrefix = "var:"
+	// explainIdKey and explainRefsKey are used when explaining an interpolated value,
+	// to show the layer which set it and the explanation of each value it references
+	explainIdKey   = "$id"
+	explainRefsKey = "$refs"
+	// explainVar explains a value which came from a variable
+	explainVar = "$var"
+)
+
+// reference matches ${a.b.c} or ${var:name} within a string, or the $${ escape
+var reference = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}`)
+
+// InterpolationError is returned when a reference within compiled config cannot be resolved
+type InterpolationError struct {
+	// Path of the value containing the reference
+	Path string
+	// Reference which could not be resolved, eg: "hailo.service.zookeeper.hosts"
+	Reference string
+	// Message describes what went wrong
+	Message string
+}
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("Cannot resolve ${%s} at %s: %s", e.Reference, e.Path, e.Message)
+}
+
+// interpolator resolves references between the paths of a compiled config, after
+// merging. A string which is a single reference takes the referenced value, of any
+// type, whereas references within a longer string must be to strings, numbers or bools.
+type interpolator struct {
+	compiled map[string]interface{}
+	// explained mirrors compiled, and is only maintained if not nil
+	explained map[string]interface{}
+	// vars are the values of ${var:name} references, which are left as they are if nil
+	vars map[string]string
+	// resolving holds the paths being resolved, to detect cycles
+	resolving map[string]bool
+	// resolved holds the paths whose references have all been resolved
+	resolved map[string]bool
+}
+
+// interpolate resolves every reference within compiled, in place, and updates
+// explained to show where referenced values came from
+func interpolate(compiled, explained map[string]interface{}, vars map[string]string) error {
+	in := &interpolator{
+		compiled:  compiled,
+		explained: explained,
+		vars:      vars,
+		resolving: make(map[string]bool),
+		resolved:  make(map[string]bool),
+	}
+	for k := range compiled {
+		if _, err := in.resolvePath([]string{k}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the node at path within node, where array elements are referred to
+// by index. If the path runs into a value which is not a container, as happens in
+// explanations when a whole subtree came from one layer, that value is returned.
+func lookup(node interface{}, path []string, explanation bool) (interface{}, bool) {
+	for _, part := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[part]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return node, explanation
+		}
+	}
+	return node, true
+}
+
+// replace sets the node at the non-empty path within node, which must exist
+func replace(node interface{}, path []string, v interface{}) {
+	parent, _ := lookup(node, path[:len(path)-1], false)
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[path[len(path)-1]] = v
+	case []interface{}:
+		i, _ := strconv.Atoi(path[len(path)-1])
+		p[i] = v
+	}
+}
+
+// resolvePath resolves every reference within the node at path, and returns the result
+func (in *interpolator) resolvePath(path []string) (interface{}, error) {
+	key := strings.Join(path, ".")
+	node, ok := lookup(in.compiled, path, false)
+	if !ok {
+		return nil, fmt.Errorf("path not found")
+	}
+	if in.resolved[key] {
+		return node, nil
+	}
+	if in.resolving[key] {
+		return nil, fmt.Errorf("reference cycle")
+	}
+	in.resolving[key] = true
+	defer delete(in.resolving, key)
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k := range n {
+			if _, err := in.resolvePath(append(path[:len(path):len(path)], k)); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i := range n {
+			if _, err := in.resolvePath(append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		resolved, err := in.resolveString(n, path)
+		if err != nil {
+			return nil, err
+		}
+		node = resolved
+		replace(in.compiled, path, node)
+	}
+
+	in.resolved[key] = true
+	return node, nil
+}
+
+// resolveString resolves the references within s, the value at path
+func (in *interpolator) resolveString(s string, path []string) (interface{}, error) {
+	matches := reference.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	refs := make(map[string]interface{})
+	fail := func(ref, message string) error {
+		return &InterpolationError{Path: strings.Join(path, "/"), Reference: ref, Message: message}
+	}
+
+	// A single reference takes the referenced value whole
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) && matches[0][2] >= 0 {
+		ref := s[matches[0][2]:matches[0][3]]
+		v, ok, err := in.resolveReference(ref, refs)
+		if err != nil {
+			return nil, fail(ref, err.Error())
+		}
+		if !ok {
+			return s, nil
+		}
+		in.explain(path, refs)
+		return deepCopy(v), nil
+	}
+
+	var resolved []string
+	last := 0
+	for _, m := range matches {
+		resolved = append(resolved, s[last:m[0]])
+		last = m[1]
+		if m[2] < 0 {
+			// Escaped
+			resolved = append(resolved, "${")
+			continue
+		}
+		ref := s[m[2]:m[3]]
+		v, ok, err := in.resolveReference(ref, refs)
+		if err != nil {
+			return nil, fail(ref, err.Error())
+		}
+		switch t := v.(type) {
+		case nil:
+			if ok {
+				return nil, fail(ref, "cannot interpolate null into a string")
+			}
+			resolved = append(resolved, s[m[0]:m[1]])
+		case string:
+			resolved = append(resolved, t)
+		case float64:
+			resolved = append(resolved, strconv.FormatFloat(t, 'f', -1, 64))
+		case bool:
+			resolved = append(resolved, strconv.FormatBool(t))
+		default:
+			return nil, fail(ref, "cannot interpolate an object or array into a string")
+		}
+	}
+	resolved = append(resolved, s[last:])
+
+	if len(refs) > 0 {
+		in.explain(path, refs)
+	}
+	return strings.Join(resolved, ""), nil
+}
+
+// resolveReference returns the value of ref, recording its explanation in refs. It
+// returns false if ref is to a variable, and no variables were given.
+func (in *interpolator) resolveReference(ref string, refs map[string]interface{}) (interface{}, bool, error) {
+	if strings.HasPrefix(ref, varPrefix) {
+		if in.vars == nil {
+			return nil, false, nil
+		}
+		v, ok := in.vars[strings.TrimPrefix(ref, varPrefix)]
+		if !ok {
+			return nil, false, fmt.Errorf("variable not given")
+		}
+		refs[ref] = explainVar
+		return v, true, nil
+	}
+
+	if ref == "" {
+		return nil, false, fmt.Errorf("empty reference")
+	}
+	path := strings.Split(ref, ".")
+	v, err := in.resolvePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if in.explained != nil {
+		e, _ := lookup(in.explained, path, true)
+		refs[ref] = deepCopy(e)
+	}
+	return v, true, nil
+}
+
+// explain records the references resolved for the value at path, alongside the
+// layer which set it
+func (in *interpolator) explain(path []string, refs map[string]interface{}) {
+	if in.explained == nil {
+		return
+	}
+	id, ok := lookup(in.explained, path, true)
+	if !ok {
+		return
+	}
+	replace(in.explained, path, map[string]interface{}{
+		explainIdKey:   id,
+		explainRefsKey: refs,
+	})
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+)
+
+func (s *DomainSuite) TestCompileInterpolation() {
+	base := `{"hailo":{"service":{"zookeeper":{"hosts":["zk01","zk02"]},"statsd":{"host":"statsd01","port":8125}}}}`
+
+	testCases := []struct {
+		layer    string
+		vars     map[string]string
+		expected string
+		errors   bool
+	}{
+		// Whole values are copied, of any type
+		{`{"foo":{"zk":"${hailo.service.zookeeper.hosts}"}}`, nil,
+			`{"zk":["zk01","zk02"]}`, false},
+		// Within strings, including array elements
+		{`{"foo":{"statsd":"${hailo.service.statsd.host}:${hailo.service.statsd.port}","first":"zk://${hailo.service.zookeeper.hosts.0}"}}`, nil,
+			`{"statsd":"statsd01:8125","first":"zk://zk01"}`, false},
+		// References to references, and escapes
+		{`{"foo":{"a":"${foo.b}","b":"${foo.c}!","c":"$${foo.a}"}}`, nil,
+			`{"a":"${foo.a}!","b":"${foo.a}!","c":"${foo.a}"}`, false},
+		// Variables
+		{`{"foo":{"region":"${var:region}-${hailo.service.statsd.host}"}}`, map[string]string{"region": "eu-west-1"},
+			`{"region":"eu-west-1-statsd01"}`, false},
+		// Variables are left alone if none are given
+		{`{"foo":{"region":"${var:region}"}}`, nil,
+			`{"region":"${var:region}"}`, false},
+		{`{"foo":{"region":"${var:region}"}}`, map[string]string{}, ``, true},
+		// Missing paths
+		{`{"foo":{"bar":"${hailo.service.memcache.hosts}"}}`, nil, ``, true},
+		// Cycles
+		{`{"foo":{"a":"${foo.b}","b":"x${foo.a}"}}`, nil, ``, true},
+		{`{"foo":{"a":"${foo}"}}`, nil, ``, true},
+		// Objects cannot be interpolated into strings
+		{`{"foo":{"a":"zk:${hailo.service.zookeeper}"}}`, nil, ``, true},
+	}
+
+	for i, tc := range testCases {
+		DefaultRepository = &memoryRepository{
+			data: map[string]*ChangeSet{
+				"a": &ChangeSet{Id: "a", Body: []byte(base), Timestamp: time.Now()},
+				"b": &ChangeSet{Id: "b", Body: []byte(tc.layer), Timestamp: time.Now()},
+			},
+		}
+
+		compiled, _, err := CompileConfigWithOptions([]string{"a", "b"}, "foo", &CompileOptions{Vars: tc.vars})
+		if tc.errors {
+			_, ok := err.(*InterpolationError)
+			s.True(ok, "Expected interpolation error for testcase %v, got %v", i, err)
+			continue
+		}
+		s.NoError(err, "Unexpected error for testcase %v", i)
+		eq, err := compareJson([]byte(tc.expected), compiled)
+		s.NoError(err)
+		s.True(eq, "Compiled config incorrect for testcase %v: %s", i, compiled)
+	}
+}
+
+func (s *DomainSuite) TestExplainInterpolation() {
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			"a": &ChangeSet{
+				Id:        "a",
+				Body:      []byte(`{"zk":{"hosts":["zk01"]},"statsd":"statsd01"}`),
+				Timestamp: time.Now(),
+			},
+			"b": &ChangeSet{
+				Id:        "b",
+				Body:      []byte(`{"zk":{"hosts":{"$merge":"append","$value":["zk02"]}},"foo":{"zk":"${zk.hosts}","statsd":"${statsd}:8125"}}`),
+				Timestamp: time.Now(),
+			},
+		},
+	}
+
+	explained, err := ExplainConfig([]string{"a", "b"}, "foo")
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"zk":{"$id":"b","$refs":{"zk.hosts":["a","b"]}},"statsd":{"$id":"b","$refs":{"statsd":"a"}}}`), explained)
+	s.NoError(err)
+	s.True(eq, "Explained config incorrect: %s", explained)
+}
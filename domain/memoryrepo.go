@@ -1,11 +1,21 @@
 package domain
 
 import (
+	"sort"
+	"strings"
 	"time"
 )
 
 type memoryRepository struct {
 	data map[string]*ChangeSet
+	// history holds every revision written, oldest first
+	history map[string][]*ChangeSet
+	// proposals are keyed by proposal ID
+	proposals map[string]*Proposal
+	// scheduled are keyed by schedule ID
+	scheduled map[string]*ScheduledChange
+	// overrides are keyed by override ID
+	overrides map[string]*Override
 }
 
 func NewMemoryRepository(data map[string]*ChangeSet) *memoryRepository {
@@ -13,22 +23,217 @@ func NewMemoryRepository(data map[string]*ChangeSet) *memoryRepository {
 }
 
 func (r memoryRepository) ReadConfig(ids []string) ([]*ChangeSet, error) {
-	configs := make([]*ChangeSet, len(ids))
-	for i := 0; i < len(ids); i++ {
-		configs[i] = r.data[ids[i]]
+	// Mirror the C* repository, which omits IDs that do not exist
+	configs := make([]*ChangeSet, 0, len(ids))
+	for _, id := range ids {
+		if cs, ok := r.data[id]; ok {
+			configs = append(configs, cs)
+		}
 	}
 	return configs, nil
 }
 
+// revisions returns the revisions of id, oldest first, including any seeded data
+// which was never written through UpdateConfig
+func (r memoryRepository) revisions(id string) []*ChangeSet {
+	revisions := r.history[id]
+	cs, ok := r.data[id]
+	if ok && (len(revisions) == 0 || revisions[len(revisions)-1] != cs) {
+		revisions = append(revisions, cs)
+	}
+	return revisions
+}
+
+func (r memoryRepository) ReadConfigAtRevision(id string, revision int64) (*ChangeSet, error) {
+	for _, cs := range r.revisions(id) {
+		if cs.Revision == revision {
+			return cs, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+func (r memoryRepository) ReadConfigAt(id string, t time.Time) (*ChangeSet, error) {
+	revisions := r.revisions(id)
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if !revisions[i].Timestamp.After(t) {
+			return revisions[i], nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
 func (r *memoryRepository) UpdateConfig(cs *ChangeSet) error {
+	if r.history == nil {
+		r.history = make(map[string][]*ChangeSet)
+	}
+	r.history[cs.Id] = r.revisions(cs.Id)
 	r.data[cs.Id] = cs
+	r.history[cs.Id] = append(r.history[cs.Id], cs)
 	return nil
 }
 
+func (r *memoryRepository) UpdateConfigs(css []*ChangeSet) error {
+	for _, cs := range css {
+		if err := r.UpdateConfig(cs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteId(cs *ChangeSet) error {
+	if r.history == nil {
+		r.history = make(map[string][]*ChangeSet)
+	}
+	r.history[cs.Id] = append(r.revisions(cs.Id), cs)
+	delete(r.data, cs.Id)
+	return nil
+}
+
+func (r *memoryRepository) ListIds(prefix, cursor string, count int) ([]*ChangeSet, string, error) {
+	ids := make([]string, 0, len(r.data))
+	for id := range r.data {
+		if strings.HasPrefix(id, prefix) && id > cursor {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	last := ""
+	if count > 0 && len(ids) > count {
+		ids = ids[:count]
+		last = ids[count-1]
+	}
+	summaries := make([]*ChangeSet, len(ids))
+	for i, id := range ids {
+		summaries[i] = r.data[id].Summary()
+	}
+	return summaries, last, nil
+}
+
 func (r *memoryRepository) ChangeLog(start, end time.Time, count int, lastId string) ([]*ChangeSet, string, error) {
-	return []*ChangeSet{}, "", nil
+	var css []*ChangeSet
+	for _, revisions := range r.history {
+		for _, cs := range revisions {
+			if !cs.Timestamp.Before(start) && !cs.Timestamp.After(end) {
+				css = append(css, cs)
+			}
+		}
+	}
+	// Newest first, as the C* time series is read
+	sort.Stable(sort.Reverse(changesByTime(css)))
+
+	if lastId != "" {
+		for i, cs := range css {
+			if cs.ChangeId == lastId {
+				css = css[i+1:]
+				break
+			}
+		}
+	}
+	if len(css) < count {
+		return css, "", nil
+	}
+	return css[:count], css[count-1].ChangeId, nil
 }
 
+type changesByTime []*ChangeSet
+
+func (css changesByTime) Len() int           { return len(css) }
+func (css changesByTime) Swap(i, j int)      { css[i], css[j] = css[j], css[i] }
+func (css changesByTime) Less(i, j int) bool { return css[i].Timestamp.Before(css[j].Timestamp) }
+
 func (r *memoryRepository) ServiceChangeLog(id string, start, end time.Time, count int, lastId string) ([]*ChangeSet, string, error) {
 	return []*ChangeSet{}, "", nil
 }
+
+func (r *memoryRepository) SaveProposal(p *Proposal) error {
+	if r.proposals == nil {
+		r.proposals = make(map[string]*Proposal)
+	}
+	// Copy, as the C* repository would
+	saved := *p
+	r.proposals[p.ProposalId] = &saved
+	return nil
+}
+
+func (r *memoryRepository) ReadProposal(proposalId string) (*Proposal, error) {
+	p, ok := r.proposals[proposalId]
+	if !ok {
+		return nil, ErrProposalNotFound
+	}
+	read := *p
+	return &read, nil
+}
+
+func (r *memoryRepository) ListProposals(id string, pending bool) ([]*Proposal, error) {
+	proposals := make([]*Proposal, 0, len(r.proposals))
+	for _, p := range r.proposals {
+		if pending && p.Status != ProposalPending {
+			continue
+		}
+		if id == "" || p.Change.Id == id {
+			read := *p
+			proposals = append(proposals, &read)
+		}
+	}
+	return proposals, nil
+}
+
+func (r *memoryRepository) SaveScheduledChange(sc *ScheduledChange) error {
+	if r.scheduled == nil {
+		r.scheduled = make(map[string]*ScheduledChange)
+	}
+	// Copy, as the C* repository would
+	saved := *sc
+	r.scheduled[sc.ScheduleId] = &saved
+	return nil
+}
+
+func (r *memoryRepository) ReadScheduledChange(scheduleId string) (*ScheduledChange, error) {
+	sc, ok := r.scheduled[scheduleId]
+	if !ok {
+		return nil, ErrScheduleNotFound
+	}
+	read := *sc
+	return &read, nil
+}
+
+func (r *memoryRepository) ListScheduledChanges(id string, pending bool) ([]*ScheduledChange, error) {
+	scs := make([]*ScheduledChange, 0, len(r.scheduled))
+	for _, sc := range r.scheduled {
+		if pending && sc.Status != SchedulePending {
+			continue
+		}
+		if id == "" || sc.Id == id {
+			read := *sc
+			scs = append(scs, &read)
+		}
+	}
+	return scs, nil
+}
+
+func (r *memoryRepository) SaveOverride(o *Override) error {
+	if r.overrides == nil {
+		r.overrides = make(map[string]*Override)
+	}
+	// Copy, as the C* repository would
+	saved := *o
+	r.overrides[o.OverrideId] = &saved
+	return nil
+}
+
+func (r *memoryRepository) ListOverrides(id string, active bool) ([]*Override, error) {
+	overrides := make([]*Override, 0, len(r.overrides))
+	for _, o := range r.overrides {
+		if active && o.Status != OverrideActive {
+			continue
+		}
+		if id == "" || o.Id == id {
+			read := *o
+			overrides = append(overrides, &read)
+		}
+	}
+	return overrides, nil
+}
@@ -0,0 +1,490 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MergeStrategy defines how an array in one layer of config is combined with the
+// same array inherited from the layers beneath it
+type MergeStrategy string
+
+const (
+	// MergeReplace replaces the inherited array entirely, and is the default
+	MergeReplace MergeStrategy = "replace"
+	// MergeAppend adds the elements after the inherited ones
+	MergeAppend MergeStrategy = "append"
+	// MergePrepend adds the elements before the inherited ones
+	MergePrepend MergeStrategy = "prepend"
+	// MergeUnion appends only those elements not already inherited
+	MergeUnion MergeStrategy = "union"
+	// MergeRemove removes the elements from the inherited ones
+	MergeRemove MergeStrategy = "remove"
+	// MergeByKey deep merges arrays of objects, matching elements on the value of a key
+	MergeByKey MergeStrategy = "mergeByKey"
+
+	// mergeDirectiveKey marks an object within a layer of config as a merge directive, eg:
+	// {"$merge": "append", "$value": ["10.0.0.4"]}
+	// {"$merge": "mergeByKey", "$key": "name", "$value": [{"name": "a", "port": 80}]}
+	mergeDirectiveKey = "$merge"
+	mergeValueKey     = "$value"
+	mergeKeyKey       = "$key"
+
+	// deleteKey marks an object within a layer of config as a deletion marker, which
+	// removes the key, and everything beneath it, inherited from lower layers: {"$delete": true}
+	deleteKey = "$delete"
+	// deletedByKey is used when explaining to show which layer deleted a key
+	deletedByKey = "$deletedBy"
+)
+
+// mergeDirective says how to merge the array at some path
+type mergeDirective struct {
+	Strategy MergeStrategy
+	// Key is the property that identifies elements when using MergeByKey
+	Key string
+}
+
+func parseMergeStrategy(strategy, key string) (*mergeDirective, error) {
+	switch MergeStrategy(strategy) {
+	case MergeReplace, MergeAppend, MergePrepend, MergeUnion, MergeRemove:
+	case MergeByKey:
+		if key == "" {
+			return nil, fmt.Errorf("Merge strategy %s requires a key", MergeByKey)
+		}
+	default:
+		return nil, fmt.Errorf("Unknown merge strategy %q", strategy)
+	}
+	return &mergeDirective{Strategy: MergeStrategy(strategy), Key: key}, nil
+}
+
+// isMergeDirective returns true if the object is a merge directive rather than config
+func isMergeDirective(m map[string]interface{}) bool {
+	_, ok := m[mergeDirectiveKey]
+	return ok
+}
+
+// isDeleteMarker returns true if the object is a deletion marker rather than config
+func isDeleteMarker(m map[string]interface{}) bool {
+	_, ok := m[deleteKey]
+	return ok
+}
+
+// parseMergeDirective returns the directive and the value it applies to
+func parseMergeDirective(m map[string]interface{}) (*mergeDirective, interface{}, error) {
+	strategy, ok := m[mergeDirectiveKey].(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s should be a string", mergeDirectiveKey)
+	}
+	key, _ := m[mergeKeyKey].(string)
+	directive, err := parseMergeStrategy(strategy, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, ok := m[mergeValueKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("Merge directive is missing %s", mergeValueKey)
+	}
+	if _, ok := value.([]interface{}); !ok && directive.Strategy != MergeReplace {
+		return nil, nil, fmt.Errorf("Merge strategy %s can only be applied to an array", directive.Strategy)
+	}
+	return directive, value, nil
+}
+
+// checkDirectives walks a decoded config making sure all merge directives, deletion
+// markers and references to secrets are valid
+func checkDirectives(node interface{}, path string) error {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if isDeleteMarker(m) {
+		if m[deleteKey] != true || len(m) != 1 {
+			return &ValidationError{Violations: []*Violation{{
+				Path:    path,
+				Message: fmt.Sprintf(`Deletion marker should be exactly {"%s": true}`, deleteKey),
+			}}}
+		}
+		return nil
+	}
+	if isMergeDirective(m) {
+		if _, _, err := parseMergeDirective(m); err != nil {
+			return &ValidationError{Violations: []*Violation{{Path: path, Message: err.Error()}}}
+		}
+		return nil
+	}
+	if isSecretRef(m) {
+		if err := checkSecretRef(m); err != nil {
+			return &ValidationError{Violations: []*Violation{{Path: path, Message: err.Error()}}}
+		}
+		return nil
+	}
+	for k, v := range m {
+		if err := checkDirectives(v, joinPath(path, k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaMergeDirectives collects the merge strategies declared within the registered
+// schemas, keyed by the config path they apply to
+func schemaMergeDirectives(schemas map[string]*Schema) map[string]*mergeDirective {
+	directives := make(map[string]*mergeDirective)
+	var walk func(s *Schema, path string)
+	walk = func(s *Schema, path string) {
+		if s.MergeStrategy != nil {
+			directives[path] = s.MergeStrategy
+		}
+		for k, prop := range s.Properties {
+			walk(prop, joinPath(path, k))
+		}
+	}
+	for path, s := range schemas {
+		walk(s, path)
+	}
+	return directives
+}
+
+// merger combines layers of config, optionally keeping track of which layer each
+// value came from
+type merger struct {
+	// directives are the merge strategies which apply by default, keyed by path
+	directives map[string]*mergeDirective
+	// strict returns an *ErrPathConflict when one layer has an object where another
+	// has some other value, rather than letting the later layer win with its whole subtree
+	strict bool
+}
+
+// conflict builds the error for a type conflict at path, where e holds the
+// explanation of the existing value
+func (m *merger) conflict(e map[string]interface{}, k, bId, path string) error {
+	var ids []string
+	if e != nil {
+		ids = explainedIds(e[k])
+	}
+	return &ErrPathConflict{Path: path, Ids: append(ids, bId)}
+}
+
+// mergeMap starts with "a" and recursively adds "b" on top
+// "a" will be modified
+// "e" mirrors "a", holding the id of the layer each value came from, and is only
+// maintained if not nil. Arrays merged by a strategy other than replace hold the
+// id for each element, and deleted keys hold {"$deletedBy": <id>}.
+// bId is the id of "b" which is used for explaining
+func (m *merger) mergeMap(a, e, b map[string]interface{}, bId, path string) error {
+	for k, v := range b {
+		p := joinPath(path, k)
+		bm, ok := v.(map[string]interface{})
+		if ok && isDeleteMarker(bm) {
+			delete(a, k)
+			if e != nil {
+				e[k] = map[string]interface{}{deletedByKey: bId}
+			}
+			continue
+		}
+		if !ok || isMergeDirective(bm) || isSecretRef(bm) {
+			// We're at a "leaf"
+			if err := m.mergeLeaf(a, e, k, v, bId, p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Keep walking, creating nodes if we need to
+		am, ok := a[k].(map[string]interface{})
+		if !ok {
+			if m.strict && a[k] != nil {
+				return m.conflict(e, k, bId, p)
+			}
+			am = make(map[string]interface{})
+			a[k] = am
+			if e != nil {
+				e[k] = make(map[string]interface{})
+			}
+		}
+		var em map[string]interface{}
+		if e != nil {
+			if em, ok = e[k].(map[string]interface{}); !ok {
+				// The object was set whole by a replace directive
+				em = explainedAs(am, e[k]).(map[string]interface{})
+				e[k] = em
+			}
+		}
+		if err := m.mergeMap(am, em, bm, bId, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeLeaf sets the value of k within "a" to v, honouring any merge strategy
+func (m *merger) mergeLeaf(a, e map[string]interface{}, k string, v interface{}, bId, path string) error {
+	directive := m.directives[path]
+	if vm, ok := v.(map[string]interface{}); ok && isMergeDirective(vm) {
+		d, value, err := parseMergeDirective(vm)
+		if err != nil {
+			return fmt.Errorf("Invalid merge directive at %s in %s: %v", path, bId, err)
+		}
+		directive, v = d, value
+	}
+
+	if m.strict {
+		// References to secrets stand in for strings
+		am, wasObject := a[k].(map[string]interface{})
+		wasObject = wasObject && !isSecretRef(am)
+		vm, isObject := v.(map[string]interface{})
+		isObject = isObject && !isSecretRef(vm)
+		if a[k] != nil && wasObject != isObject {
+			return m.conflict(e, k, bId, path)
+		}
+	}
+
+	values, ok := v.([]interface{})
+	if directive == nil || directive.Strategy == MergeReplace || !ok {
+		// Replace final node
+		a[k] = v
+		if e != nil {
+			e[k] = bId
+		}
+		return nil
+	}
+
+	inherited, _ := a[k].([]interface{})
+	var provenance []interface{}
+	if e != nil {
+		provenance = elementProvenance(e[k], len(inherited))
+	}
+	a[k], provenance = mergeArrays(inherited, provenance, values, bId, directive)
+	if e != nil {
+		e[k] = provenance
+	}
+	return nil
+}
+
+// explainedAs returns the explanation of v when all of it came from the same id
+func explainedAs(v interface{}, id interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return id
+	}
+	explained := make(map[string]interface{}, len(m))
+	for k, child := range m {
+		explained[k] = explainedAs(child, id)
+	}
+	return explained
+}
+
+// explainedIds returns the sorted, distinct, ids found within an explanation
+func explainedIds(explained interface{}) []string {
+	seen := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case string:
+			seen[t] = true
+		case []interface{}:
+			for _, child := range t {
+				walk(child)
+			}
+		case map[string]interface{}:
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+	walk(explained)
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// elementProvenance expands the explanation of an array into one id per element
+func elementProvenance(explained interface{}, n int) []interface{} {
+	if ids, ok := explained.([]interface{}); ok && len(ids) == n {
+		return append([]interface{}{}, ids...)
+	}
+	ids := make([]interface{}, n)
+	for i := range ids {
+		ids[i] = explained
+	}
+	return ids
+}
+
+// mergeArrays combines the inherited array with values from layer bId according to
+// the directive, returning the new array along with the id each element came from
+func mergeArrays(inherited, provenance, values []interface{}, bId string, directive *mergeDirective) ([]interface{}, []interface{}) {
+	merged := append([]interface{}{}, inherited...)
+	if provenance == nil {
+		provenance = make([]interface{}, len(inherited))
+	}
+	ids := func(n int) []interface{} {
+		ids := make([]interface{}, n)
+		for i := range ids {
+			ids[i] = bId
+		}
+		return ids
+	}
+
+	switch directive.Strategy {
+	case MergeAppend:
+		merged = append(merged, values...)
+		provenance = append(provenance, ids(len(values))...)
+
+	case MergePrepend:
+		merged = append(append([]interface{}{}, values...), merged...)
+		provenance = append(ids(len(values)), provenance...)
+
+	case MergeUnion:
+		for _, v := range values {
+			if indexOf(merged, v) == -1 {
+				merged = append(merged, v)
+				provenance = append(provenance, bId)
+			}
+		}
+
+	case MergeRemove:
+		keptValues := make([]interface{}, 0, len(merged))
+		keptIds := make([]interface{}, 0, len(merged))
+		for i, v := range merged {
+			if indexOf(values, v) == -1 {
+				keptValues = append(keptValues, v)
+				keptIds = append(keptIds, provenance[i])
+			}
+		}
+		merged, provenance = keptValues, keptIds
+
+	case MergeByKey:
+		for _, v := range values {
+			obj, ok := v.(map[string]interface{})
+			key, hasKey := obj[directive.Key]
+			i := -1
+			if ok && hasKey {
+				i = indexByKey(merged, directive.Key, key)
+			}
+			if i == -1 {
+				merged = append(merged, v)
+				provenance = append(provenance, bId)
+				continue
+			}
+			merged[i] = mergeValues(merged[i].(map[string]interface{}), obj)
+			provenance[i] = bId
+		}
+	}
+
+	return merged, provenance
+}
+
+func indexOf(values []interface{}, v interface{}) int {
+	for i, candidate := range values {
+		if reflect.DeepEqual(candidate, v) {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexByKey(values []interface{}, key string, v interface{}) int {
+	for i, candidate := range values {
+		if obj, ok := candidate.(map[string]interface{}); ok && reflect.DeepEqual(obj[key], v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeValues returns a copy of "a" with "b" deep merged on top
+func mergeValues(a, b map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(a))
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range b {
+		bm, bok := v.(map[string]interface{})
+		am, aok := merged[k].(map[string]interface{})
+		if aok && bok {
+			merged[k] = mergeValues(am, bm)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// applyMergePatch applies patch to target as an RFC 7396 JSON Merge Patch, returning
+// the result. As with mergeMap, merge directives and deletion markers within the patch
+// are leaves which replace the target, rather than being merged into it.
+func applyMergePatch(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok || isMergeDirective(pm) || isDeleteMarker(pm) {
+		return patch
+	}
+
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = make(map[string]interface{})
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = applyMergePatch(tm[k], v)
+	}
+	return tm
+}
+
+// mergeConfigs merges the given configs, in order, into a single config. When
+// explaining, the result holds the id each value came from rather than the value.
+// When strict, type conflicts between layers are returned as an *ErrPathConflict.
+// References such as "${hailo.service.zookeeper.hosts}" are then resolved, and
+// secrets are masked unless they are to be revealed.
+func mergeConfigs(configs []*ChangeSet, explain bool, opts *CompileOptions) (map[string]interface{}, error) {
+	var compiled, explained map[string]interface{}
+	if len(configs) == 0 {
+		return compiled, nil
+	}
+
+	schemas, err := readCachedSchemas()
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &CompileOptions{}
+	}
+	m := &merger{directives: schemaMergeDirectives(schemas), strict: opts.Strict}
+
+	compiled = make(map[string]interface{})
+	if explain || opts.Strict {
+		explained = make(map[string]interface{})
+	}
+
+	// Merge!
+	for _, cs := range configs {
+		var config map[string]interface{}
+		err := json.Unmarshal(cs.Body, &config)
+		if err != nil {
+			return nil, fmt.Errorf("Error unmarshalling config: %v", err)
+		}
+		if err := m.mergeMap(compiled, explained, config, cs.Id, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := interpolate(compiled, explained, opts.Vars); err != nil {
+		return nil, err
+	}
+	if _, err := resolveSecrets(compiled, opts.RevealSecrets); err != nil {
+		return nil, err
+	}
+
+	if explain {
+		return explained, nil
+	}
+	return compiled, nil
+}
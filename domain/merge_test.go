@@ -0,0 +1,282 @@
+package domain
+
+import (
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestCompileMergeStrategies() {
+	base := `{"hailo":{"service":{"cassandra":{"hosts":["a","b","c"]}}}}`
+
+	testCases := []struct {
+		schema    string
+		layer     string
+		expected  string
+		explained string
+	}{
+		// Default is to replace
+		{``, `{"hailo":{"service":{"cassandra":{"hosts":["d"]}}}}`,
+			`{"hosts":["d"]}`, `{"hosts":"b"}`},
+		{``, `{"hailo":{"service":{"cassandra":{"hosts":{"$merge":"append","$value":["d"]}}}}}`,
+			`{"hosts":["a","b","c","d"]}`, `{"hosts":["a","a","a","b"]}`},
+		{``, `{"hailo":{"service":{"cassandra":{"hosts":{"$merge":"prepend","$value":["d"]}}}}}`,
+			`{"hosts":["d","a","b","c"]}`, `{"hosts":["b","a","a","a"]}`},
+		{``, `{"hailo":{"service":{"cassandra":{"hosts":{"$merge":"union","$value":["c","d"]}}}}}`,
+			`{"hosts":["a","b","c","d"]}`, `{"hosts":["a","a","a","b"]}`},
+		{``, `{"hailo":{"service":{"cassandra":{"hosts":{"$merge":"remove","$value":["b"]}}}}}`,
+			`{"hosts":["a","c"]}`, `{"hosts":["a","a"]}`},
+		{``, `{"hailo":{"service":{"cassandra":{"hosts":{"$merge":"replace","$value":["d"]}}}}}`,
+			`{"hosts":["d"]}`, `{"hosts":"b"}`},
+		// Strategy declared in the schema registry
+		{`{"hailo":{"service":{"cassandra":{"_schema":{"properties":{"hosts":{"mergeStrategy":"append"}}}}}}}`,
+			`{"hailo":{"service":{"cassandra":{"hosts":["d"]}}}}`,
+			`{"hosts":["a","b","c","d"]}`, `{"hosts":["a","a","a","b"]}`},
+		// Layer directive overrides the schema
+		{`{"hailo":{"service":{"cassandra":{"_schema":{"properties":{"hosts":{"mergeStrategy":"append"}}}}}}}`,
+			`{"hailo":{"service":{"cassandra":{"hosts":{"$merge":"replace","$value":["d"]}}}}}`,
+			`{"hosts":["d"]}`, `{"hosts":"b"}`},
+	}
+
+	for i, tc := range testCases {
+		data := map[string]*ChangeSet{
+			"a": &ChangeSet{Id: "a", Body: []byte(base), Timestamp: time.Now()},
+			"b": &ChangeSet{Id: "b", Body: []byte(tc.layer), Timestamp: time.Now()},
+		}
+		if tc.schema != "" {
+			data[SchemaId] = &ChangeSet{Id: SchemaId, Body: []byte(tc.schema), Timestamp: time.Now()}
+		}
+		DefaultRepository = &memoryRepository{data: data}
+
+		compiled, err := CompileConfig([]string{"a", "b"}, "hailo/service/cassandra")
+		s.NoError(err)
+		eq, err := compareJson([]byte(tc.expected), compiled)
+		s.NoError(err)
+		s.True(eq, "Compiled config incorrect for testcase %v: %s", i, compiled)
+
+		explained, err := ExplainConfig([]string{"a", "b"}, "hailo/service/cassandra")
+		s.NoError(err)
+		eq, err = compareJson([]byte(tc.explained), explained)
+		s.NoError(err)
+		s.True(eq, "Explained config incorrect for testcase %v: %s", i, explained)
+	}
+}
+
+func (s *DomainSuite) TestCompileCachesSchemas() {
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			"a": &ChangeSet{Id: "a", Body: []byte(`{"hosts":["a"]}`), Timestamp: time.Now()},
+			"b": &ChangeSet{Id: "b", Body: []byte(`{"hosts":["b"]}`), Timestamp: time.Now()},
+		},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(SchemaId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	compile := func() string {
+		compiled, err := CompileConfig([]string{"a", "b"}, "")
+		s.NoError(err)
+		return string(compiled)
+	}
+	s.Equal(`{"hosts":["b"]}`, compile())
+
+	// Not read again, until written through this instance
+	testRepo.data[SchemaId] = &ChangeSet{Id: SchemaId, Body: []byte(`{"_schema":{"properties":{"hosts":{"mergeStrategy":"append"}}}}`)}
+	s.Equal(`{"hosts":["b"]}`, compile())
+
+	s.NoError(CreateOrUpdateConfig("c", SchemaId, "", "h2", "dave", "Append hosts", []byte(`{"_schema":{"properties":{"hosts":{"mergeStrategy":"append"}}}}`), nil))
+	s.Equal(`{"hosts":["a","b"]}`, compile())
+}
+
+func (s *DomainSuite) TestCompileMergeByKey() {
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			"a": &ChangeSet{
+				Id:        "a",
+				Body:      []byte(`{"backends":[{"name":"x","port":1,"tls":{"enabled":false}},{"name":"y","port":2}]}`),
+				Timestamp: time.Now(),
+			},
+			"b": &ChangeSet{
+				Id:        "b",
+				Body:      []byte(`{"backends":{"$merge":"mergeByKey","$key":"name","$value":[{"name":"x","tls":{"enabled":true}},{"name":"z","port":3}]}}`),
+				Timestamp: time.Now(),
+			},
+		},
+	}
+
+	compiled, err := CompileConfig([]string{"a", "b"}, "")
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"backends":[{"name":"x","port":1,"tls":{"enabled":true}},{"name":"y","port":2},{"name":"z","port":3}]}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+
+	explained, err := ExplainConfig([]string{"a", "b"}, "")
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"backends":["b","a","b"]}`), explained)
+	s.NoError(err)
+	s.True(eq, "Explained config incorrect: %s", explained)
+}
+
+func (s *DomainSuite) TestInvalidMergeDirective() {
+	DefaultRepository = &memoryRepository{data: map[string]*ChangeSet{}}
+
+	err := saveConfig(&ChangeSet{
+		Id:   "a",
+		Body: []byte(`{"hosts":{"$merge":"mergeByKey","$value":[]}}`),
+	}, nil)
+	_, ok := err.(*ValidationError)
+	s.True(ok, "Expected validation error, got %v", err)
+}
+
+func (s *DomainSuite) TestCompileDeletionMarkers() {
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			"a": &ChangeSet{
+				Id:        "a",
+				Body:      []byte(`{"hailo":{"service1":{"value1":10,"value2":20},"service2":{"value1":10}}}`),
+				Timestamp: time.Now(),
+			},
+			"b": &ChangeSet{
+				Id:        "b",
+				Body:      []byte(`{"hailo":{"service1":{"value2":{"$delete":true}},"service2":{"$delete":true}}}`),
+				Timestamp: time.Now(),
+			},
+			"c": &ChangeSet{
+				Id:        "c",
+				Body:      []byte(`{"hailo":{"service2":{"value3":30}}}`),
+				Timestamp: time.Now(),
+			},
+		},
+	}
+
+	compiled, err := CompileConfig([]string{"a", "b"}, "")
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"hailo":{"service1":{"value1":10}}}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+
+	explained, err := ExplainConfig([]string{"a", "b"}, "")
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"hailo":{"service1":{"value1":"a","value2":{"$deletedBy":"b"}},"service2":{"$deletedBy":"b"}}}`), explained)
+	s.NoError(err)
+	s.True(eq, "Explained config incorrect: %s", explained)
+
+	// A higher layer can add the key back again
+	compiled, err = CompileConfig([]string{"a", "b", "c"}, "hailo/service2")
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"value3":30}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+
+	explained, err = ExplainConfig([]string{"a", "b", "c"}, "hailo/service2")
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"value3":"c"}`), explained)
+	s.NoError(err)
+	s.True(eq, "Explained config incorrect: %s", explained)
+
+	// Before and after a change
+	before, after, err := CompileConfigWithChange([]string{"a", "b"}, "b", "hailo/service1/value1", []byte(`{"$delete":true}`))
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"hailo":{"service1":{"value1":10}}}`), before)
+	s.NoError(err)
+	s.True(eq, "Compiled config before change incorrect: %s", before)
+	eq, err = compareJson([]byte(`{"hailo":{"service1":{}}}`), after)
+	s.NoError(err)
+	s.True(eq, "Compiled config after change incorrect: %s", after)
+
+	// Markers must be exactly {"$delete": true}
+	err = saveConfig(&ChangeSet{Id: "d", Body: []byte(`{"foo":{"$delete":true,"bar":1}}`)}, nil)
+	_, ok := err.(*ValidationError)
+	s.True(ok, "Expected validation error, got %v", err)
+}
+
+func (s *DomainSuite) TestCompileTypeConflicts() {
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			"a": &ChangeSet{
+				Id:        "a",
+				Body:      []byte(`{"foo":"x","bar":{"baz":1},"qux":{"$merge":"replace","$value":{"a":1}}}`),
+				Timestamp: time.Now(),
+			},
+			"b": &ChangeSet{
+				Id:        "b",
+				Body:      []byte(`{"foo":{"bar":1},"bar":2,"qux":{"b":2}}`),
+				Timestamp: time.Now(),
+			},
+		},
+	}
+
+	// The later layer wins with its whole subtree
+	compiled, err := CompileConfig([]string{"a", "b"}, "")
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"foo":{"bar":1},"bar":2,"qux":{"a":1,"b":2}}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+
+	explained, err := ExplainConfig([]string{"a", "b"}, "")
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"foo":{"bar":"b"},"bar":"b","qux":{"a":"a","b":"b"}}`), explained)
+	s.NoError(err)
+	s.True(eq, "Explained config incorrect: %s", explained)
+
+	// Strict mode reports the conflict
+	strict := &CompileOptions{Strict: true}
+	_, _, err = CompileConfigWithOptions([]string{"a", "b"}, "", strict)
+	conflict, ok := err.(*ErrPathConflict)
+	s.True(ok, "Expected path conflict, got %v", err)
+	if ok {
+		s.Contains([]string{"foo", "bar"}, conflict.Path)
+		s.Equal([]string{"a", "b"}, conflict.Ids)
+	}
+
+	_, _, err = CompileConfigWithOptions([]string{"b", "a"}, "", strict)
+	_, ok = err.(*ErrPathConflict)
+	s.True(ok, "Expected path conflict, got %v", err)
+
+	compiled, _, err = CompileConfigWithOptions([]string{"a"}, "", strict)
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"foo":"x","bar":{"baz":1},"qux":{"a":1}}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config incorrect: %s", compiled)
+}
+
+func (s *DomainSuite) TestWriteTypeConflicts() {
+	id := "a"
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{
+				Id:        id,
+				Body:      []byte(`{"foo":"x","bar":null}`),
+				Timestamp: time.Now(),
+			},
+		},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	err := CreateOrUpdateConfig("foo", id, "foo/bar", "h2", "dave", "Test Message", []byte(`1`), nil)
+	conflict, ok := err.(*ErrPathConflict)
+	s.True(ok, "Expected path conflict, got %v", err)
+	if ok {
+		s.Equal("foo", conflict.Path)
+	}
+
+	err = DeleteConfig("foo", id, "foo/bar/baz", "h2", "dave", "Test Message", nil)
+	conflict, ok = err.(*ErrPathConflict)
+	s.True(ok, "Expected path conflict, got %v", err)
+	if ok {
+		s.Equal("foo", conflict.Path)
+	}
+
+	// Null is treated as missing
+	err = CreateOrUpdateConfig("foo", id, "bar/baz", "h2", "dave", "Test Message", []byte(`1`), nil)
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"foo":"x","bar":{"baz":1}}`), testRepo.data[id].Body)
+	s.NoError(err)
+	s.True(eq, "Updated config incorrect: %s", testRepo.data[id].Body)
+}
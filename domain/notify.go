@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"sync"
+)
+
+var (
+	changedMtx sync.Mutex
+	// changed is closed, and replaced, each time this instance writes config
+	changed = make(chan struct{})
+)
+
+// Changed returns a channel which is closed the next time this instance writes any
+// config. Changes written by other instances are not seen, so anything waiting on it
+// should also check again from time to time.
+func Changed() <-chan struct{} {
+	changedMtx.Lock()
+	defer changedMtx.Unlock()
+	return changed
+}
+
+// notifyChange wakes everything waiting on Changed, once the changes css have been
+// written, and forgets any cached schemas if the registry was among them
+func notifyChange(css ...*ChangeSet) {
+	for _, cs := range css {
+		if cs.Id == SchemaId {
+			forgetSchemas()
+		}
+	}
+
+	changedMtx.Lock()
+	defer changedMtx.Unlock()
+	close(changed)
+	changed = make(chan struct{})
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+// isClosed returns true if ch has been closed
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *DomainSuite) TestChanged() {
+	id := "H2:BASE"
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{}`), Revision: 1, Timestamp: time.Now()},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	changed := Changed()
+	s.False(isClosed(changed))
+
+	// Failed writes change nothing
+	err := CreateOrUpdateConfig("c0", id, "", "h2", "dave", "Bad", []byte(`[]`), nil)
+	s.Error(err)
+	s.False(isClosed(changed))
+
+	err = CreateOrUpdateConfig("c1", id, "foo", "h2", "dave", "Foo", []byte(`"bar"`), nil)
+	s.NoError(err)
+	s.True(isClosed(changed))
+
+	// Later writes are waited on afresh
+	s.False(isClosed(Changed()))
+}
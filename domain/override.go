@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	platformsync "github.com/HailoOSS/service/sync"
+)
+
+const (
+	// reaperLockId is locked by whichever instance is reverting expired overrides, so that
+	// only one does at a time. It is beneath a node of its own, apart from the locks of
+	// config IDs.
+	reaperLockId = "_service/reaper"
+)
+
+// OverrideStatus says whether a temporary override is still in place
+type OverrideStatus string
+
+const (
+	// OverrideActive is in place until it expires
+	OverrideActive OverrideStatus = "active"
+	// OverrideReverted has expired, and the config from before it restored
+	OverrideReverted OverrideStatus = "reverted"
+	// OverrideSuperseded was changed again before it expired, so was left as it was
+	OverrideSuperseded OverrideStatus = "superseded"
+	// OverrideFailed could not be reverted, eg: as the config before it is no longer valid
+	OverrideFailed OverrideStatus = "failed"
+)
+
+var (
+	ErrExpiryInPast = errors.New("Overrides can only expire in the future")
+)
+
+// Override is an update which is reverted once it expires, restoring the config at its
+// path from before it
+type Override struct {
+	// OverrideId is the ChangeId of the update
+	OverrideId string
+	Id         string
+	Path       string
+	// Config is that set at the path by the override
+	Config []byte
+	// OldConfig is restored on expiry, and is empty if there was nothing at the path
+	OldConfig []byte
+	// Hash of Config, which must still be at the path for the override to be reverted
+	Hash      string
+	ExpiresAt time.Time
+	UserMech  string
+	UserId    string
+	Message   string
+	Timestamp time.Time
+	Status    OverrideStatus
+	// RevertChangeId is the ChangeId of the revert, once reverted
+	RevertChangeId string
+	// Error says why the override could not be reverted, if it failed
+	Error string
+}
+
+// saveOverride records the update cs, already saved, as a temporary override expiring at
+// opts.ExpiresAt. An active override of the same path is folded into it, so that the
+// config from before both is what is restored.
+func saveOverride(cs *ChangeSet, opts *WriteOptions) error {
+	config, err := readConfigAtPath(cs.Body, cs.Path)
+	if err != nil {
+		return fmt.Errorf("Error getting config at path %s : %v", cs.Path, err)
+	}
+
+	o := &Override{
+		OverrideId: cs.ChangeId,
+		Id:         cs.Id,
+		Path:       cs.Path,
+		Config:     config,
+		OldConfig:  cs.OldConfig,
+		Hash:       ConfigHash(config),
+		ExpiresAt:  opts.ExpiresAt,
+		UserMech:   cs.UserMech,
+		UserId:     cs.UserId,
+		Message:    cs.Message,
+		Timestamp:  cs.Timestamp,
+		Status:     OverrideActive,
+	}
+
+	active, err := ListOverrides(cs.Id, true)
+	if err != nil {
+		return err
+	}
+	for _, prev := range active {
+		if prev.Path != cs.Path {
+			continue
+		}
+		o.OldConfig = prev.OldConfig
+		prev.Status = OverrideSuperseded
+		if err := DefaultRepository.SaveOverride(prev); err != nil {
+			return fmt.Errorf("Error saving override: %v", err)
+		}
+	}
+
+	if err := DefaultRepository.SaveOverride(o); err != nil {
+		return fmt.Errorf("Error saving override: %v", err)
+	}
+	return nil
+}
+
+// ListOverrides returns the temporary overrides of id, or any ID if empty, soonest to
+// expire first. If active is set, only those still in place are returned.
+func ListOverrides(id string, active bool) ([]*Override, error) {
+	overrides, err := DefaultRepository.ListOverrides(id, active)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Override, 0, len(overrides))
+	for _, o := range overrides {
+		if active && o.Status != OverrideActive {
+			continue
+		}
+		ret = append(ret, o)
+	}
+	sort.Sort(overridesByExpiry(ret))
+	return ret, nil
+}
+
+type overridesByExpiry []*Override
+
+func (o overridesByExpiry) Len() int           { return len(o) }
+func (o overridesByExpiry) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o overridesByExpiry) Less(i, j int) bool { return o[i].ExpiresAt.Before(o[j].ExpiresAt) }
+
+// revert restores the config from before the override, so long as the override is
+// still what is at its path
+func (o *Override) revert(changeId, userMech, userId string) error {
+	opts := &WriteOptions{
+		ExpectedHash: o.Hash,
+		Reverts:      o.OverrideId,
+	}
+	message := fmt.Sprintf("Reverted expired override %s: %s", o.OverrideId, o.Message)
+
+	switch {
+	case len(o.OldConfig) > 0:
+		return CreateOrUpdateConfig(changeId, o.Id, o.Path, userMech, userId, message, o.OldConfig, opts)
+	case o.Path == "":
+		return CreateOrUpdateConfig(changeId, o.Id, o.Path, userMech, userId, message, []byte(`{}`), opts)
+	default:
+		return DeleteConfig(changeId, o.Id, o.Path, userMech, userId, message, opts)
+	}
+}
+
+// RevertExpiredOverrides restores the config from before each active override expired by
+// now, as a change made by userMech and userId with an ID from newChangeId, returning
+// those overrides whose status changed. Overrides whose path has changed since are left
+// as they are, and marked superseded, while those in a freeze window stay active until
+// it ends. Only one instance of the service reverts overrides at a time, as whichever
+// holds the reaper lock.
+func RevertExpiredOverrides(now time.Time, userMech, userId string, newChangeId func() (string, error)) ([]*Override, error) {
+	lock, err := platformsync.RegionLock([]byte(reaperLockId))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	active, err := ListOverrides("", true)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []*Override
+	for _, o := range active {
+		if o.ExpiresAt.After(now) {
+			break
+		}
+		changeId, err := newChangeId()
+		if err != nil {
+			return expired, err
+		}
+
+		err = o.revert(changeId, userMech, userId)
+		if _, ok := err.(*FrozenError); ok {
+			continue
+		}
+		switch err {
+		case nil:
+			o.Status = OverrideReverted
+			o.RevertChangeId = changeId
+		case ErrConfigChanged:
+			o.Status = OverrideSuperseded
+		default:
+			o.Status = OverrideFailed
+			o.Error = err.Error()
+		}
+		if err := DefaultRepository.SaveOverride(o); err != nil {
+			return expired, fmt.Errorf("Error saving override: %v", err)
+		}
+		expired = append(expired, o)
+	}
+	return expired, nil
+}
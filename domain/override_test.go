@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestOverrides() {
+	id := "H2:BASE"
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{"zookeeper":{"timeout":"1s"}}`), Revision: 1, Timestamp: time.Now()},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+	s.zk.
+		On("NewLock", lockPath(reaperLockId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	n := 0
+	newChangeId := func() (string, error) {
+		n++
+		return fmt.Sprintf("revert%d", n), nil
+	}
+
+	now := time.Now()
+	err := CreateOrUpdateConfig("o0", id, "zookeeper/timeout", "h2", "dave", "Past", []byte(`"5s"`), &WriteOptions{ExpiresAt: now.Add(-time.Minute)})
+	s.Equal(ErrExpiryInPast, err)
+
+	// An override of an existing value, extended by another, and one of a new value
+	err = CreateOrUpdateConfig("o1", id, "zookeeper/timeout", "h2", "dave", "Incident", []byte(`"5s"`), &WriteOptions{ExpiresAt: now.Add(time.Minute)})
+	s.NoError(err)
+	err = CreateOrUpdateConfig("o2", id, "zookeeper/timeout", "h2", "dave", "Still going", []byte(`"10s"`), &WriteOptions{ExpiresAt: now.Add(2 * time.Minute)})
+	s.NoError(err)
+	err = CreateOrUpdateConfig("o3", id, "log/level", "h2", "dave", "Debug", []byte(`"debug"`), &WriteOptions{ExpiresAt: now.Add(time.Minute)})
+	s.NoError(err)
+	_, cs, err := ReadConfig(id, "")
+	s.NoError(err)
+	s.False(cs.ExpiresAt.IsZero())
+
+	active, err := ListOverrides(id, true)
+	s.NoError(err)
+	s.Len(active, 2)
+	s.Equal("o3", active[0].OverrideId)
+	s.Equal("o2", active[1].OverrideId)
+	s.Equal(`"1s"`, string(active[1].OldConfig))
+
+	// Nothing is reverted before it expires
+	reverted, err := RevertExpiredOverrides(now, "s2s", "config", newChangeId)
+	s.NoError(err)
+	s.Len(reverted, 0)
+
+	reverted, err = RevertExpiredOverrides(now.Add(5*time.Minute), "s2s", "config", newChangeId)
+	s.NoError(err)
+	s.Len(reverted, 2)
+	for _, o := range reverted {
+		s.Equal(OverrideReverted, o.Status)
+	}
+	config, cs, err := ReadConfig(id, "")
+	s.NoError(err)
+	s.Equal(`{"log":{},"zookeeper":{"timeout":"1s"}}`, string(config))
+	s.Equal("config", cs.UserId)
+	s.Equal("o2", cs.Reverts)
+
+	// Overrides changed since are left as they are
+	err = CreateOrUpdateConfig("o4", id, "zookeeper/timeout", "h2", "dave", "Incident", []byte(`"5s"`), &WriteOptions{ExpiresAt: now.Add(time.Minute)})
+	s.NoError(err)
+	err = CreateOrUpdateConfig("c1", id, "zookeeper/timeout", "h2", "bob", "Keep it", []byte(`"3s"`), nil)
+	s.NoError(err)
+	reverted, err = RevertExpiredOverrides(now.Add(5*time.Minute), "s2s", "config", newChangeId)
+	s.NoError(err)
+	s.Len(reverted, 1)
+	s.Equal(OverrideSuperseded, reverted[0].Status)
+	config, _, err = ReadConfig(id, "zookeeper/timeout")
+	s.NoError(err)
+	s.Equal(`"3s"`, string(config))
+
+	all, err := ListOverrides("", false)
+	s.NoError(err)
+	s.Len(all, 4)
+	active, err = ListOverrides("", true)
+	s.NoError(err)
+	s.Len(active, 0)
+}
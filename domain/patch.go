@@ -0,0 +1,329 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchError is returned when an RFC 6902 JSON Patch cannot be applied
+type PatchError struct {
+	// Index of the failing operation within the patch, or -1 if the patch as a whole is invalid
+	Index int
+	// Op is the failing operation, eg: "add"
+	Op string
+	// Path the operation applies to
+	Path string
+	// Message describes what went wrong
+	Message string
+	// TestFailed is set if a "test" operation found a different value, ie: a precondition failed
+	TestFailed bool
+}
+
+func (e *PatchError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("Invalid patch: %s", e.Message)
+	}
+	return fmt.Sprintf("Patch operation %d (%s %q) failed: %s", e.Index, e.Op, e.Path, e.Message)
+}
+
+// patchOperation is a single operation within a JSON Patch
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer %q should start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses token as an index into an array, which must be no more than max
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of bounds", i)
+	}
+	return i, nil
+}
+
+// patchGet returns the value within node which tokens refer to
+func patchGet(node interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			node = child
+		case []interface{}:
+			i, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("path not found")
+		}
+	}
+	return node, nil
+}
+
+// patchAt walks node to the parent of the location tokens refer to, replacing the parent
+// with the result of f, and returns the updated node. tokens must not be empty.
+func patchAt(node interface{}, tokens []string, f func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return f(node, tokens[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path not found")
+		}
+		updated, err := patchAt(child, tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = updated
+		return n, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := patchAt(n[i], tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = updated
+		return n, nil
+	}
+	return nil, fmt.Errorf("path not found")
+}
+
+func patchAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchAt(doc, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[key] = value
+			return p, nil
+		case []interface{}:
+			if key == "-" {
+				return append(p, value), nil
+			}
+			i, err := arrayIndex(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+}
+
+func patchRemove(doc interface{}, tokens []string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove the whole document")
+	}
+	return patchAt(doc, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[key]; !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			delete(p, key)
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(key, len(p)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+}
+
+func patchReplace(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchAt(doc, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[key]; !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			p[key] = value
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(key, len(p)-1)
+			if err != nil {
+				return nil, err
+			}
+			p[i] = value
+			return p, nil
+		}
+		return nil, fmt.Errorf("path not found")
+	})
+}
+
+// deepCopy returns a copy of a decoded JSON value which shares nothing with v
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, child := range t {
+			m[k] = deepCopy(child)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i, child := range t {
+			a[i] = deepCopy(child)
+		}
+		return a
+	}
+	return v
+}
+
+// applyPatch applies the RFC 6902 JSON Patch to doc. Either every operation is
+// applied, or a *PatchError is returned; doc may be modified either way.
+func applyPatch(doc interface{}, patch []byte) (interface{}, error) {
+	var ops []*patchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, &PatchError{Index: -1, Message: fmt.Sprintf("patch should be a JSON array of operations: %v", err)}
+	}
+
+	for i, op := range ops {
+		fail := func(err error) error {
+			pe := &PatchError{Index: i, Op: op.Op, Message: err.Error()}
+			if op.Path != nil {
+				pe.Path = *op.Path
+			}
+			return pe
+		}
+
+		if op.Path == nil {
+			return nil, fail(fmt.Errorf("missing path"))
+		}
+		tokens, err := parsePointer(*op.Path)
+		if err != nil {
+			return nil, fail(err)
+		}
+
+		var value interface{}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fail(fmt.Errorf("missing value"))
+			}
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return nil, fail(err)
+			}
+		case "move", "copy":
+			if op.From == nil {
+				return nil, fail(fmt.Errorf("missing from"))
+			}
+			from, err := parsePointer(*op.From)
+			if err != nil {
+				return nil, fail(err)
+			}
+			if value, err = patchGet(doc, from); err != nil {
+				return nil, fail(fmt.Errorf("from %v", err))
+			}
+			if op.Op == "copy" {
+				value = deepCopy(value)
+				break
+			}
+			if *op.Path != *op.From && strings.HasPrefix(*op.Path, *op.From+"/") {
+				return nil, fail(fmt.Errorf("cannot move a value into itself"))
+			}
+			if doc, err = patchRemove(doc, from); err != nil {
+				return nil, fail(err)
+			}
+		}
+
+		switch op.Op {
+		case "add", "move", "copy":
+			doc, err = patchAdd(doc, tokens, value)
+		case "remove":
+			doc, err = patchRemove(doc, tokens)
+		case "replace":
+			doc, err = patchReplace(doc, tokens, value)
+		case "test":
+			current, err := patchGet(doc, tokens)
+			if err == nil && !reflect.DeepEqual(current, value) {
+				err = fmt.Errorf("value differs")
+			}
+			if err != nil {
+				pe := fail(err).(*PatchError)
+				pe.TestFailed = true
+				return nil, pe
+			}
+		default:
+			err = fmt.Errorf("unknown operation")
+		}
+		if err != nil {
+			return nil, fail(err)
+		}
+	}
+
+	return doc, nil
+}
+
+// PatchPaths returns the "/" separated paths which a JSON Patch changes
+func PatchPaths(patch []byte) ([]string, error) {
+	var ops []*patchOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, &PatchError{Index: -1, Message: fmt.Sprintf("patch should be a JSON array of operations: %v", err)}
+	}
+
+	var paths []string
+	for i, op := range ops {
+		pointers := []*string{op.Path}
+		switch op.Op {
+		case "test":
+			continue
+		case "move":
+			pointers = append(pointers, op.From)
+		}
+		for _, pointer := range pointers {
+			if pointer == nil {
+				return nil, &PatchError{Index: i, Op: op.Op, Message: "missing path"}
+			}
+			tokens, err := parsePointer(*pointer)
+			if err != nil {
+				return nil, &PatchError{Index: i, Op: op.Op, Path: *pointer, Message: err.Error()}
+			}
+			paths = append(paths, strings.Join(tokens, "/"))
+		}
+	}
+	return paths, nil
+}
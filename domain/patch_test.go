@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestApplyPatch() {
+	testCases := []struct {
+		doc        string
+		patch      string
+		expected   string
+		testFailed bool
+	}{
+		{`{"foo":1}`, `[{"op":"add","path":"/bar","value":{"baz":[1,2]}}]`, `{"foo":1,"bar":{"baz":[1,2]}}`, false},
+		{`{"foo":[1,2]}`, `[{"op":"add","path":"/foo/1","value":3},{"op":"add","path":"/foo/-","value":4}]`, `{"foo":[1,3,2,4]}`, false},
+		{`{"foo":1,"bar":2}`, `[{"op":"remove","path":"/foo"}]`, `{"bar":2}`, false},
+		{`{"foo":[1,2,3]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":[1,3]}`, false},
+		{`{"foo":1}`, `[{"op":"replace","path":"/foo","value":null}]`, `{"foo":null}`, false},
+		{`{"foo":{"bar":1},"baz":{}}`, `[{"op":"move","from":"/foo/bar","path":"/baz/qux"}]`, `{"foo":{},"baz":{"qux":1}}`, false},
+		{`{"foo":{"bar":[1]}}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"add","path":"/baz/bar/-","value":2}]`, `{"foo":{"bar":[1]},"baz":{"bar":[1,2]}}`, false},
+		{`{"a/b":{"c~d":1}}`, `[{"op":"test","path":"/a~1b/c~0d","value":1},{"op":"replace","path":"/a~1b/c~0d","value":2}]`, `{"a/b":{"c~d":2}}`, false},
+		// Failing preconditions
+		{`{"foo":1}`, `[{"op":"test","path":"/foo","value":2},{"op":"replace","path":"/foo","value":3}]`, ``, true},
+		{`{"foo":1}`, `[{"op":"test","path":"/bar","value":1}]`, ``, true},
+		// Invalid operations
+		{`{"foo":1}`, `[{"op":"replace","path":"/bar","value":1}]`, ``, false},
+		{`{"foo":1}`, `[{"op":"remove","path":"/foo/bar"}]`, ``, false},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/2","value":1}]`, ``, false},
+		{`{"foo":[1]}`, `[{"op":"add","path":"/foo/01","value":1}]`, ``, false},
+		{`{"foo":{}}`, `[{"op":"move","from":"/foo","path":"/foo/bar"}]`, ``, false},
+		{`{"foo":1}`, `[{"op":"add","path":"/bar"}]`, ``, false},
+		{`{"foo":1}`, `[{"op":"frob","path":"/foo"}]`, ``, false},
+		{`{"foo":1}`, `{"op":"add","path":"/foo","value":1}`, ``, false},
+	}
+
+	for i, tc := range testCases {
+		var doc interface{}
+		s.NoError(json.Unmarshal([]byte(tc.doc), &doc))
+
+		patched, err := applyPatch(doc, []byte(tc.patch))
+		if tc.expected == "" {
+			perr, ok := err.(*PatchError)
+			s.True(ok, "Expected patch error for testcase %v, got %v", i, err)
+			if ok {
+				s.Equal(tc.testFailed, perr.TestFailed, "Test failure incorrect for testcase %v", i)
+			}
+			continue
+		}
+
+		s.NoError(err, "Unexpected error for testcase %v", i)
+		var expected interface{}
+		s.NoError(json.Unmarshal([]byte(tc.expected), &expected))
+		s.Equal(expected, patched, "Patched document incorrect for testcase %v", i)
+	}
+}
+
+func (s *DomainSuite) TestPatchConfig() {
+	id := "a"
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{
+				Id:        id,
+				Body:      []byte(`{"foo":{"bar":1},"baz":[1]}`),
+				Timestamp: time.Now(),
+				Revision:  1,
+			},
+		},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	// Nothing is written if any operation fails
+	err := PatchConfig("c", id, "h2", "dave", "Test Message", []byte(`[{"op":"replace","path":"/foo/bar","value":2},{"op":"remove","path":"/qux"}]`), nil)
+	_, ok := err.(*PatchError)
+	s.True(ok, "Expected patch error, got %v", err)
+	s.Equal(int64(1), testRepo.data[id].Revision)
+
+	err = PatchConfig("c", id, "h2", "dave", "Test Message", []byte(`[{"op":"remove","path":""}]`), nil)
+	_, ok = err.(*PatchError)
+	s.True(ok, "Expected patch error, got %v", err)
+
+	err = PatchConfig("c", id, "h2", "dave", "Test Message", []byte(`[{"op":"replace","path":"","value":[]}]`), nil)
+	_, ok = err.(*PatchError)
+	s.True(ok, "Expected patch error, got %v", err)
+
+	patch := `[{"op":"test","path":"/foo/bar","value":1},{"op":"replace","path":"/foo/bar","value":2},{"op":"add","path":"/baz/-","value":2}]`
+	s.NoError(PatchConfig("c", id, "h2", "dave", "Test Message", []byte(patch), nil))
+
+	cs := testRepo.data[id]
+	eq, err := compareJson([]byte(`{"foo":{"bar":2},"baz":[1,2]}`), cs.Body)
+	s.NoError(err)
+	s.True(eq, "Patched config incorrect: %s", cs.Body)
+	s.Equal(int64(2), cs.Revision)
+	s.Equal(patch, string(cs.Patch))
+	s.Equal(`{"foo":{"bar":1},"baz":[1]}`, string(cs.OldConfig))
+}
+
+func (s *DomainSuite) TestPatchPaths() {
+	paths, err := PatchPaths([]byte(`[{"op":"test","path":"/a","value":1},{"op":"replace","path":"/b/c~1d","value":2},{"op":"move","from":"/e","path":"/f"},{"op":"copy","from":"/g","path":"/h"}]`))
+	s.NoError(err)
+	s.Equal([]string{"b/c/d", "f", "e", "h"}, paths)
+
+	_, err = PatchPaths([]byte(`[{"op":"remove"}]`))
+	_, ok := err.(*PatchError)
+	s.True(ok, "Expected patch error, got %v", err)
+}
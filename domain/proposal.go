@@ -0,0 +1,320 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	pathpkg "path"
+	"sort"
+	"time"
+
+	platformsync "github.com/HailoOSS/service/sync"
+)
+
+const (
+	// ApprovalId is the config ID under which the IDs that can only be changed by an
+	// approved proposal are listed, eg: {"ids":["H2:BASE","H2:REGION:*"]}
+	ApprovalId = "APPROVAL"
+)
+
+// ProposalStatus says where a proposal is in its review
+type ProposalStatus string
+
+const (
+	// ProposalPending is awaiting review
+	ProposalPending ProposalStatus = "pending"
+	// ProposalApproved has been applied
+	ProposalApproved ProposalStatus = "approved"
+	// ProposalRejected will never be applied
+	ProposalRejected ProposalStatus = "rejected"
+	// ProposalStale can no longer be applied, as its ID has changed since it was proposed
+	ProposalStale ProposalStatus = "stale"
+)
+
+var (
+	ErrProposalNotFound = errors.New("Proposal not found")
+	ErrProposalClosed   = errors.New("Proposal is no longer pending")
+	ErrProposalStale    = errors.New("Config has changed since the proposal was made")
+	ErrSelfReview       = errors.New("Proposals must be reviewed by someone other than the proposer")
+)
+
+// Proposal is a change which is only applied once reviewed and approved by another user
+type Proposal struct {
+	ProposalId string
+	// Change is applied as it is on approval, so long as its ID is still at the revision
+	// before, and so also carries the diff against the config at the time
+	Change           *ChangeSet
+	SkipValidation   bool
+	ValidateCompiled bool
+	Status           ProposalStatus
+	// Reviewer of the proposal, once approved or rejected
+	ReviewerMech  string
+	ReviewerId    string
+	ReviewMessage string
+	ReviewedAt    time.Time
+}
+
+// ChangedConfig returns the config at the path of the change, after it is applied
+func (p *Proposal) ChangedConfig() ([]byte, error) {
+	config, err := readConfigAtPath(p.Change.Body, p.Change.Path)
+	if err == ErrPathNotFound {
+		return []byte{}, nil
+	}
+	return config, err
+}
+
+// approvalDefinition is the body stored under ApprovalId
+type approvalDefinition struct {
+	// Ids are patterns, where * matches any characters
+	Ids []string `json:"ids"`
+}
+
+// parseApprovalPolicy returns the ID patterns of an approval policy
+func parseApprovalPolicy(body []byte) ([]string, error) {
+	def := &approvalDefinition{}
+	if err := json.Unmarshal(body, def); err != nil {
+		return nil, &ValidationError{Violations: []*Violation{{Message: fmt.Sprintf("Approval policy is not valid: %v", err)}}}
+	}
+
+	var violations []*Violation
+	for i, id := range def.Ids {
+		if _, err := pathpkg.Match(id, ""); id == "" || err != nil {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("ids/%d", i),
+				Message: fmt.Sprintf("Invalid ID pattern %q", id),
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return def.Ids, nil
+}
+
+// checkApprovalPolicy makes sure a change to the approval policy is valid
+func checkApprovalPolicy(id string, body []byte) error {
+	if id != ApprovalId {
+		return nil
+	}
+	_, err := parseApprovalPolicy(body)
+	return err
+}
+
+// RequiresApproval returns true if id may only be changed by an approved proposal
+func RequiresApproval(id string) (bool, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{ApprovalId})
+	if err != nil {
+		return false, fmt.Errorf("Error getting approval policy from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return false, nil
+	}
+	patterns, err := parseApprovalPolicy(configs[0].Body)
+	if err != nil {
+		return false, err
+	}
+	for _, pattern := range patterns {
+		if ok, _ := pathpkg.Match(pattern, id); ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Propose validates a change to the config for id at path, as CreateOrUpdateConfig would
+// make it, and stores it as a pending proposal for someone else to approve or reject
+func Propose(proposalId, id, path, userMech, userId, message string, data []byte, opts *WriteOptions) (*Proposal, error) {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+
+	var newNode interface{}
+	if err := json.Unmarshal(data, &newNode); err != nil {
+		return nil, fmt.Errorf("New value is not valid JSON: %v", err)
+	}
+
+	configs, err := DefaultRepository.ReadConfig([]string{id})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+
+	cs, err := updateChangeSet(configs, proposalId, id, path, userMech, userId, message, data, newNode, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := prepareConfig(cs, opts, nil); err != nil {
+		return nil, err
+	}
+
+	p := &Proposal{
+		ProposalId:       proposalId,
+		Change:           cs,
+		SkipValidation:   opts.SkipValidation,
+		ValidateCompiled: opts.ValidateCompiled,
+		Status:           ProposalPending,
+	}
+	if err := DefaultRepository.SaveProposal(p); err != nil {
+		return nil, fmt.Errorf("Error saving proposal: %v", err)
+	}
+	return p, nil
+}
+
+// isStale returns true if the ID of a pending proposal has changed since it was proposed
+func (p *Proposal) isStale() (bool, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{p.Change.Id})
+	if err != nil {
+		return false, fmt.Errorf("Error getting config from DAO: %v", err)
+	}
+	revision, err := nextRevision(p.Change.Id, configs)
+	if err != nil {
+		return false, err
+	}
+	return revision != p.Change.Revision, nil
+}
+
+// checkStale marks a pending proposal as stale if its ID has changed since it was proposed
+func (p *Proposal) checkStale() error {
+	if p.Status != ProposalPending {
+		return nil
+	}
+	stale, err := p.isStale()
+	if err != nil {
+		return err
+	}
+	if stale {
+		p.Status = ProposalStale
+	}
+	return nil
+}
+
+// ReadProposal returns a proposal, which is shown as stale if still pending but its ID
+// has changed since
+func ReadProposal(proposalId string) (*Proposal, error) {
+	p, err := DefaultRepository.ReadProposal(proposalId)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkStale(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ListProposals returns the proposals to change id, or any ID if empty, oldest first.
+// If pending is set, only those which could still be approved are returned.
+func ListProposals(id string, pending bool) ([]*Proposal, error) {
+	proposals, err := DefaultRepository.ListProposals(id, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Proposal, 0, len(proposals))
+	for _, p := range proposals {
+		if err := p.checkStale(); err != nil {
+			return nil, err
+		}
+		if pending && p.Status != ProposalPending {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	sort.Sort(proposalsByTime(ret))
+	return ret, nil
+}
+
+type proposalsByTime []*Proposal
+
+func (ps proposalsByTime) Len() int      { return len(ps) }
+func (ps proposalsByTime) Swap(i, j int) { ps[i], ps[j] = ps[j], ps[i] }
+func (ps proposalsByTime) Less(i, j int) bool {
+	return ps[i].Change.Timestamp.Before(ps[j].Change.Timestamp)
+}
+
+// reviewProposal locks the ID of a pending proposal, checks the reviewer is not the
+// proposer, under any mechanism, and calls f to review it before saving the outcome
+func reviewProposal(proposalId, userMech, userId, message string, f func(p *Proposal) error) (*Proposal, error) {
+	p, err := DefaultRepository.ReadProposal(proposalId)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := platformsync.RegionLock([]byte(p.Change.Id))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	// Read it again now nothing else can apply it
+	if p, err = DefaultRepository.ReadProposal(proposalId); err != nil {
+		return nil, err
+	}
+	if p.Status != ProposalPending {
+		return nil, ErrProposalClosed
+	}
+	if p.Change.UserId == userId {
+		return nil, ErrSelfReview
+	}
+
+	if err := f(p); err != nil {
+		return nil, err
+	}
+	p.ReviewerMech = userMech
+	p.ReviewerId = userId
+	p.ReviewMessage = message
+	p.ReviewedAt = time.Now()
+	if err := DefaultRepository.SaveProposal(p); err != nil {
+		return nil, fmt.Errorf("Error saving proposal: %v", err)
+	}
+	return p, nil
+}
+
+// ApproveProposal applies a pending proposal, on behalf of someone other than the
+// proposer. If breakGlass is set it gets through any freeze window, justified by the
+// approver's message. If its ID has changed since it was proposed, it is marked stale
+// and ErrProposalStale returned, as the change may no longer be what was reviewed.
+func ApproveProposal(proposalId, userMech, userId, message string, breakGlass bool) (*Proposal, error) {
+	if breakGlass && message == "" {
+		return nil, ErrBreakGlassMessage
+	}
+
+	return reviewProposal(proposalId, userMech, userId, message, func(p *Proposal) error {
+		stale, err := p.isStale()
+		if err != nil {
+			return err
+		}
+		if stale {
+			p.Status = ProposalStale
+			if err := DefaultRepository.SaveProposal(p); err != nil {
+				return fmt.Errorf("Error saving proposal: %v", err)
+			}
+			return ErrProposalStale
+		}
+
+		cs := *p.Change
+		cs.Timestamp = time.Now()
+		cs.ProposalId = p.ProposalId
+		cs.ReviewerMech = userMech
+		cs.ReviewerId = userId
+		cs.ReviewMessage = message
+		if err := saveConfig(&cs, &WriteOptions{
+			SkipValidation:    p.SkipValidation,
+			ValidateCompiled:  p.ValidateCompiled,
+			BreakGlass:        breakGlass,
+			breakGlassMessage: message,
+		}); err != nil {
+			return err
+		}
+		p.Change = &cs
+		p.Status = ProposalApproved
+		return nil
+	})
+}
+
+// RejectProposal closes a pending proposal without applying it, on behalf of someone
+// other than the proposer
+func RejectProposal(proposalId, userMech, userId, message string) (*Proposal, error) {
+	return reviewProposal(proposalId, userMech, userId, message, func(p *Proposal) error {
+		p.Status = ProposalRejected
+		return nil
+	})
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestProposals() {
+	id := "H2:BASE"
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id:         &ChangeSet{Id: id, Body: []byte(`{"cassandra":{"hosts":["c01"]}}`), Revision: 1, Timestamp: time.Now()},
+			ApprovalId: &ChangeSet{Id: ApprovalId, Body: []byte(`{"ids":["H2:*"]}`), Timestamp: time.Now()},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	required, err := RequiresApproval(id)
+	s.NoError(err)
+	s.True(required)
+	required, err = RequiresApproval("CITY:LON")
+	s.NoError(err)
+	s.False(required)
+
+	p, err := Propose("p1", id, "cassandra/hosts", "h2", "dave", "Add c02", []byte(`["c01","c02"]`), nil)
+	s.NoError(err)
+	s.Equal(ProposalPending, p.Status)
+	s.Equal(`["c01"]`, string(p.Change.OldConfig))
+	changed, err := p.ChangedConfig()
+	s.NoError(err)
+	s.Equal(`["c01","c02"]`, string(changed))
+
+	// Nothing changes until approved, and not by the proposer
+	config, _, err := ReadConfig(id, "")
+	s.NoError(err)
+	s.Equal(`{"cassandra":{"hosts":["c01"]}}`, string(config))
+	_, err = ApproveProposal("p1", "h2", "dave", "LGTM", false)
+	s.Equal(ErrSelfReview, err)
+	_, err = ApproveProposal("p1", "s2s", "dave", "LGTM", false)
+	s.Equal(ErrSelfReview, err)
+
+	p, err = ApproveProposal("p1", "h2", "bob", "LGTM", false)
+	s.NoError(err)
+	s.Equal(ProposalApproved, p.Status)
+	s.Equal("bob", p.ReviewerId)
+	config, cs, err := ReadConfig(id, "")
+	s.NoError(err)
+	s.Equal(`{"cassandra":{"hosts":["c01","c02"]}}`, string(config))
+	s.Equal("p1", cs.ChangeId)
+	s.Equal("dave", cs.UserId)
+	s.Equal(int64(2), cs.Revision)
+	s.Equal("p1", cs.ProposalId)
+	s.Equal("bob", cs.ReviewerId)
+	s.Equal("LGTM", cs.ReviewMessage)
+
+	_, err = RejectProposal("p1", "h2", "bob", "Oops")
+	s.Equal(ErrProposalClosed, err)
+
+	// Proposals are stale once their ID changes
+	_, err = Propose("p2", id, "cassandra/hosts", "h2", "dave", "Add c03", []byte(`["c01","c02","c03"]`), nil)
+	s.NoError(err)
+	_, err = Propose("p3", id, "cassandra/port", "h2", "dave", "Port", []byte(`9160`), nil)
+	s.NoError(err)
+	pending, err := ListProposals(id, true)
+	s.NoError(err)
+	s.Len(pending, 2)
+
+	_, err = ApproveProposal("p3", "h2", "bob", "", false)
+	s.NoError(err)
+	p, err = ReadProposal("p2")
+	s.NoError(err)
+	s.Equal(ProposalStale, p.Status)
+	_, err = ApproveProposal("p2", "h2", "bob", "", false)
+	s.Equal(ErrProposalStale, err)
+	p, err = DefaultRepository.ReadProposal("p2")
+	s.NoError(err)
+	s.Equal(ProposalStale, p.Status)
+
+	all, err := ListProposals("", false)
+	s.NoError(err)
+	s.Len(all, 3)
+	pending, err = ListProposals(id, true)
+	s.NoError(err)
+	s.Empty(pending)
+
+	// Proposals are validated as they are made
+	_, err = Propose("p4", ApprovalId, "", "h2", "dave", "Bad", []byte(`{"ids":["["]}`), nil)
+	_, ok := err.(*ValidationError)
+	s.True(ok, "Expected validation error, got %v", err)
+	_, err = ReadProposal("p4")
+	s.Equal(ErrProposalNotFound, err)
+}
+
+func (s *DomainSuite) TestApproveProposalDuringFreeze() {
+	id := "H2:BASE"
+	now := time.Now().UTC()
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{"foo":1}`), Revision: 1, Timestamp: now},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	_, err := Propose("p1", id, "foo", "h2", "dave", "Bump foo", []byte(`2`), nil)
+	s.NoError(err)
+
+	DefaultRepository.(*memoryRepository).data[FreezeId] = &ChangeSet{Id: FreezeId, Timestamp: now, Body: []byte(fmt.Sprintf(
+		`{"windows":[{"start":%q,"end":%q,"reason":"Peak"}]}`,
+		now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339)))}
+
+	_, err = ApproveProposal("p1", "h2", "bob", "LGTM", false)
+	_, ok := err.(*FrozenError)
+	s.True(ok, "Expected frozen error, got %v", err)
+
+	// The approver must justify breaking glass, whatever the proposer said
+	_, err = ApproveProposal("p1", "h2", "bob", "", true)
+	s.Equal(ErrBreakGlassMessage, err)
+
+	p, err := ApproveProposal("p1", "h2", "bob", "Needed for the outage", true)
+	s.NoError(err)
+	s.Equal(ProposalApproved, p.Status)
+	s.True(p.Change.BreakGlass)
+	s.Equal("Needed for the outage", p.ReviewMessage)
+	config, _, err := ReadConfig(id, "foo")
+	s.NoError(err)
+	s.Equal(`2`, string(config))
+}
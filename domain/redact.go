@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// RedactionId is the config ID under which the policy for redacting sensitive values
+	// from the audit trail and platform events is defined, eg:
+	// {"keys":["password","secret","apiKey"],"paths":["hailo/service/foo/token"]}
+	RedactionId = "REDACTION"
+
+	// redactedPrefix starts the string which replaces a redacted value, and is followed by
+	// a hash of the value so changes can still be spotted
+	redactedPrefix = "redacted:sha256:"
+)
+
+// defaultRedactionKeys are used until a policy is stored under RedactionId
+var defaultRedactionKeys = []string{"password", "secret", "apiKey"}
+
+// redactionDefinition is the body stored under RedactionId
+type redactionDefinition struct {
+	// Keys are regular expressions, matched case insensitively against the name of each
+	// key; any value under a matching key is redacted
+	Keys []string `json:"keys"`
+	// Paths are "/" separated paths within any config whose values are redacted
+	Paths []string `json:"paths"`
+}
+
+// RedactionPolicy says which values within config are sensitive
+type RedactionPolicy struct {
+	keys  []*regexp.Regexp
+	paths map[string]bool
+}
+
+// DefaultRedactionPolicy returns the policy used until one is stored under RedactionId
+func DefaultRedactionPolicy() *RedactionPolicy {
+	policy, _ := newRedactionPolicy(&redactionDefinition{Keys: defaultRedactionKeys})
+	return policy
+}
+
+func newRedactionPolicy(def *redactionDefinition) (*RedactionPolicy, error) {
+	policy := &RedactionPolicy{paths: make(map[string]bool, len(def.Paths))}
+
+	var violations []*Violation
+	for i, key := range def.Keys {
+		re, err := regexp.Compile("(?i)" + key)
+		if key == "" || err != nil {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("keys/%d", i),
+				Message: fmt.Sprintf("Invalid key pattern %q", key),
+			})
+			continue
+		}
+		policy.keys = append(policy.keys, re)
+	}
+	for i, path := range def.Paths {
+		path = strings.Trim(path, "/")
+		if path == "" {
+			violations = append(violations, &Violation{
+				Path:    fmt.Sprintf("paths/%d", i),
+				Message: "Path should not be empty",
+			})
+			continue
+		}
+		policy.paths[path] = true
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return policy, nil
+}
+
+// parseRedactionPolicy returns the policy defined by body
+func parseRedactionPolicy(body []byte) (*RedactionPolicy, error) {
+	def := &redactionDefinition{}
+	if err := json.Unmarshal(body, def); err != nil {
+		return nil, &ValidationError{Violations: []*Violation{{Message: fmt.Sprintf("Redaction policy is not valid: %v", err)}}}
+	}
+	return newRedactionPolicy(def)
+}
+
+// checkRedactionPolicy makes sure a change to the redaction policy is valid
+func checkRedactionPolicy(id string, body []byte) error {
+	if id != RedactionId {
+		return nil
+	}
+	_, err := parseRedactionPolicy(body)
+	return err
+}
+
+// ReadRedactionPolicy returns the stored policy for redacting sensitive values, or the
+// default policy if none is stored
+func ReadRedactionPolicy() (*RedactionPolicy, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{RedactionId})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting redaction policy from DAO: %v", err)
+	}
+	if len(configs) != 1 {
+		return DefaultRedactionPolicy(), nil
+	}
+	return parseRedactionPolicy(configs[0].Body)
+}
+
+// sensitive returns true if the value at path, whose last key is key, should be redacted
+func (p *RedactionPolicy) sensitive(path, key string) bool {
+	if p.paths[path] {
+		return true
+	}
+	for _, re := range p.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedValue hashes the value at path within id, so the same value always redacts to
+// the same string there, while the value itself cannot be recovered by comparing hashes
+// from elsewhere
+func redactedValue(id, path string, v interface{}) string {
+	b, _ := json.Marshal(v)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", id, path)
+	h.Write(b)
+	return redactedPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// redactNode replaces each sensitive value within node, which sits at path within id,
+// with its hash, returning the resulting node
+func (p *RedactionPolicy) redactNode(id, path string, node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if isSecretRef(n) {
+			// Only the name of the secret is here, which is not sensitive
+			return n
+		}
+		for k, v := range n {
+			childPath := joinPath(path, k)
+			if p.sensitive(childPath, k) {
+				n[k] = redactedValue(id, childPath, v)
+				continue
+			}
+			n[k] = p.redactNode(id, childPath, v)
+		}
+	case []interface{}:
+		for i, v := range n {
+			childPath := joinPath(path, fmt.Sprintf("%d", i))
+			if p.paths[childPath] {
+				n[i] = redactedValue(id, childPath, v)
+				continue
+			}
+			n[i] = p.redactNode(id, childPath, v)
+		}
+	}
+	return node
+}
+
+// Redact returns body, the config at path within id, with each sensitive value replaced
+// by a hash of it. Bodies which are not JSON are redacted whole.
+func (p *RedactionPolicy) Redact(id, path string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return []byte(fmt.Sprintf("%q", redactedValue(id, path, string(body))))
+	}
+
+	key := path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		key = path[i+1:]
+	}
+	if path != "" && p.sensitive(path, key) {
+		decoded = redactedValue(id, path, decoded)
+	} else {
+		decoded = p.redactNode(id, path, decoded)
+	}
+
+	b, err := json.Marshal(decoded)
+	if err != nil {
+		return []byte(fmt.Sprintf("%q", redactedValue(id, path, string(body))))
+	}
+	return b
+}
+
+// RedactPatch returns patch, a JSON Patch applied to id, with the sensitive values of
+// each operation replaced by a hash of them
+func (p *RedactionPolicy) RedactPatch(id string, patch []byte) []byte {
+	if len(patch) == 0 {
+		return patch
+	}
+	var ops []map[string]interface{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return []byte(fmt.Sprintf("%q", redactedValue(id, "", string(patch))))
+	}
+	for _, op := range ops {
+		value, ok := op["value"]
+		if !ok {
+			continue
+		}
+		pointer, _ := op["path"].(string)
+		tokens, err := parsePointer(pointer)
+		if err != nil {
+			op["value"] = redactedValue(id, pointer, value)
+			continue
+		}
+		b, _ := json.Marshal(value)
+		op["value"] = json.RawMessage(p.Redact(id, strings.Join(tokens, "/"), b))
+	}
+	b, err := json.Marshal(ops)
+	if err != nil {
+		return []byte(fmt.Sprintf("%q", redactedValue(id, "", string(patch))))
+	}
+	return b
+}
+
+// RedactChange returns a copy of the change with every sensitive value within its
+// config replaced by a hash of it
+func (p *RedactionPolicy) RedactChange(c *ChangeSet) *ChangeSet {
+	redacted := *c
+	redacted.Body = p.Redact(c.Id, "", c.Body)
+	if c.Deleted {
+		redacted.OldConfig = p.Redact(c.Id, "", c.OldConfig)
+	} else {
+		redacted.OldConfig = p.Redact(c.Id, c.Path, c.OldConfig)
+	}
+	redacted.Patch = p.RedactPatch(c.Id, c.Patch)
+	redacted.MergePatch = p.Redact(c.Id, c.Path, c.MergePatch)
+	redacted.NewConfig = p.Redact(c.Id, c.Path, c.NewConfig)
+	return &redacted
+}
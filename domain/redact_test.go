@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+func (s *DomainSuite) TestRedact() {
+	policy := DefaultRedactionPolicy()
+
+	body := []byte(`{"monitoring":{"apiUser":"hailo","apiPassword":"hunter2","auth":{"$secret":"monitoring/auth"}},"hosts":["a","b"]}`)
+	redacted := policy.Redact("a", "", body)
+	s.False(strings.Contains(string(redacted), "hunter2"), "Value not redacted: %s", redacted)
+	eq, err := compareJson([]byte(`{"monitoring":{"apiUser":"hailo","apiPassword":"`+redactedValue("a", "monitoring/apiPassword", "hunter2")+`","auth":{"$secret":"monitoring/auth"}},"hosts":["a","b"]}`), redacted)
+	s.NoError(err)
+	s.True(eq, "Redacted config incorrect: %s", redacted)
+
+	// The same value always hashes the same at the same path, whatever the path of the change
+	s.Equal(`"`+redactedValue("a", "monitoring/apiPassword", "hunter2")+`"`, string(policy.Redact("a", "monitoring/apiPassword", []byte(`"hunter2"`))))
+	s.NotEqual(redactedValue("a", "monitoring/apiPassword", "hunter2"), redactedValue("a", "monitoring/apiPassword", "hunter3"))
+	s.NotEqual(redactedValue("a", "monitoring/apiPassword", "hunter2"), redactedValue("b", "monitoring/apiPassword", "hunter2"))
+
+	patch := policy.RedactPatch("a", []byte(`[{"op":"replace","path":"/monitoring/apiPassword","value":"hunter2"},{"op":"remove","path":"/hosts/0"}]`))
+	eq, err = compareJson([]byte(`[{"op":"replace","path":"/monitoring/apiPassword","value":"`+redactedValue("a", "monitoring/apiPassword", "hunter2")+`"},{"op":"remove","path":"/hosts/0"}]`), patch)
+	s.NoError(err)
+	s.True(eq, "Redacted patch incorrect: %s", patch)
+
+	// A stored policy replaces the default
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			RedactionId: &ChangeSet{Id: RedactionId, Body: []byte(`{"keys":["^token$"],"paths":["hosts/1"]}`), Timestamp: time.Now()},
+		},
+	}
+	policy, err = ReadRedactionPolicy()
+	s.NoError(err)
+	redacted = policy.Redact("a", "", []byte(`{"apiPassword":"hunter2","token":"abc","hosts":["a","b"]}`))
+	eq, err = compareJson([]byte(`{"apiPassword":"hunter2","token":"`+redactedValue("a", "token", "abc")+`","hosts":["a","`+redactedValue("a", "hosts/1", "b")+`"]}`), redacted)
+	s.NoError(err)
+	s.True(eq, "Redacted config incorrect: %s", redacted)
+
+	for _, body := range []string{`{"keys":["("]}`, `{"paths":[""]}`, `[]`} {
+		_, ok := checkRedactionPolicy(RedactionId, []byte(body)).(*ValidationError)
+		s.True(ok, "Expected invalid policy %s to fail", body)
+	}
+}
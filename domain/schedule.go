@@ -0,0 +1,249 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	platformsync "github.com/HailoOSS/service/sync"
+)
+
+const (
+	// schedulerLockId is locked by whichever instance is applying scheduled changes, so
+	// that only one does at a time, and by cancellations so they cannot race them. It is
+	// beneath a node of its own, apart from the locks of config IDs.
+	schedulerLockId = "_service/scheduler"
+)
+
+// ScheduleStatus says whether a scheduled change has been applied
+type ScheduleStatus string
+
+const (
+	// SchedulePending is waiting for its time to come
+	SchedulePending ScheduleStatus = "pending"
+	// ScheduleApplied has been made
+	ScheduleApplied ScheduleStatus = "applied"
+	// ScheduleFailed could not be made when it fell due, eg: as it was invalid by then
+	ScheduleFailed ScheduleStatus = "failed"
+	// ScheduleCancelled will never be made
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("Scheduled change not found")
+	ErrScheduleClosed   = errors.New("Scheduled change is no longer pending")
+	ErrScheduleInPast   = errors.New("Changes can only be scheduled in the future")
+)
+
+// ScheduledChange is an update, or a patch, to be made to an ID at a future time
+type ScheduledChange struct {
+	ScheduleId string
+	Id         string
+	// Path and Config are those of an update, while Patch is set instead for a patch
+	Path   string
+	Config []byte
+	Merge  bool
+	Patch  []byte
+	// ApplyAt is when the change is made, by a scheduler within the service
+	ApplyAt time.Time
+	// UserMech, UserId and Message are those of whoever scheduled it, and are recorded
+	// against the change when it is made
+	UserMech         string
+	UserId           string
+	Message          string
+	Timestamp        time.Time
+	SkipValidation   bool
+	ValidateCompiled bool
+	BreakGlass       bool
+	Status           ScheduleStatus
+	// ChangeId of the change made, once applied, which is the same as ScheduleId
+	ChangeId string
+	// PreviousConfig is the config at Path, or the whole config for a patch, just before
+	// the change was applied
+	PreviousConfig []byte
+	// Error says why the change could not be made, if it failed
+	Error string
+	// Cancellation, if cancelled
+	CancelledMech    string
+	CancelledId      string
+	CancelledMessage string
+	CancelledAt      time.Time
+}
+
+// IsPatch returns true if the scheduled change is a JSON Patch, rather than an update
+func (sc *ScheduledChange) IsPatch() bool {
+	return len(sc.Patch) > 0
+}
+
+// ScheduleChange stores an update, or if patch is set a JSON Patch, to be made to the
+// config for id once applyAt comes. The change is only validated against the config
+// then, and if it fails it is recorded as such rather than retried.
+func ScheduleChange(scheduleId, id, path, userMech, userId, message string, config, patch []byte, applyAt time.Time, opts *WriteOptions) (*ScheduledChange, error) {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	now := time.Now()
+	if !applyAt.After(now) {
+		return nil, ErrScheduleInPast
+	}
+	if opts.BreakGlass && message == "" {
+		return nil, ErrBreakGlassMessage
+	}
+
+	if len(patch) > 0 {
+		if _, err := PatchPaths(patch); err != nil {
+			return nil, &ValidationError{Violations: []*Violation{{Message: err.Error()}}}
+		}
+	} else {
+		var decoded interface{}
+		if err := json.Unmarshal(config, &decoded); err != nil {
+			return nil, &ValidationError{Violations: []*Violation{{Path: path, Message: fmt.Sprintf("New value is not valid JSON: %v", err)}}}
+		}
+	}
+
+	sc := &ScheduledChange{
+		ScheduleId:       scheduleId,
+		Id:               id,
+		Path:             path,
+		Config:           config,
+		Merge:            opts.Merge,
+		Patch:            patch,
+		ApplyAt:          applyAt,
+		UserMech:         userMech,
+		UserId:           userId,
+		Message:          message,
+		Timestamp:        now,
+		SkipValidation:   opts.SkipValidation,
+		ValidateCompiled: opts.ValidateCompiled,
+		BreakGlass:       opts.BreakGlass,
+		Status:           SchedulePending,
+	}
+	if len(patch) > 0 {
+		sc.Path = ""
+		sc.Config = nil
+		sc.Merge = false
+	}
+	if err := DefaultRepository.SaveScheduledChange(sc); err != nil {
+		return nil, fmt.Errorf("Error saving scheduled change: %v", err)
+	}
+	return sc, nil
+}
+
+// ReadScheduledChange returns a scheduled change
+func ReadScheduledChange(scheduleId string) (*ScheduledChange, error) {
+	return DefaultRepository.ReadScheduledChange(scheduleId)
+}
+
+// ListScheduledChanges returns the changes scheduled for id, or any ID if empty, in the
+// order they apply. If pending is set, only those yet to be applied are returned.
+func ListScheduledChanges(id string, pending bool) ([]*ScheduledChange, error) {
+	scs, err := DefaultRepository.ListScheduledChanges(id, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*ScheduledChange, 0, len(scs))
+	for _, sc := range scs {
+		if pending && sc.Status != SchedulePending {
+			continue
+		}
+		ret = append(ret, sc)
+	}
+	sort.Sort(scheduledByTime(ret))
+	return ret, nil
+}
+
+type scheduledByTime []*ScheduledChange
+
+func (scs scheduledByTime) Len() int           { return len(scs) }
+func (scs scheduledByTime) Swap(i, j int)      { scs[i], scs[j] = scs[j], scs[i] }
+func (scs scheduledByTime) Less(i, j int) bool { return scs[i].ApplyAt.Before(scs[j].ApplyAt) }
+
+// CancelScheduledChange stops a pending scheduled change from ever being made
+func CancelScheduledChange(scheduleId, userMech, userId, message string) (*ScheduledChange, error) {
+	lock, err := platformsync.RegionLock([]byte(schedulerLockId))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	sc, err := DefaultRepository.ReadScheduledChange(scheduleId)
+	if err != nil {
+		return nil, err
+	}
+	if sc.Status != SchedulePending {
+		return nil, ErrScheduleClosed
+	}
+
+	sc.Status = ScheduleCancelled
+	sc.CancelledMech = userMech
+	sc.CancelledId = userId
+	sc.CancelledMessage = message
+	sc.CancelledAt = time.Now()
+	if err := DefaultRepository.SaveScheduledChange(sc); err != nil {
+		return nil, fmt.Errorf("Error saving scheduled change: %v", err)
+	}
+	return sc, nil
+}
+
+// apply makes the scheduled change, as whoever scheduled it, under the lock for its ID
+func (sc *ScheduledChange) apply() error {
+	opts := &WriteOptions{
+		SkipValidation:   sc.SkipValidation,
+		ValidateCompiled: sc.ValidateCompiled,
+		Merge:            sc.Merge,
+		BreakGlass:       sc.BreakGlass,
+		ScheduleId:       sc.ScheduleId,
+	}
+	if sc.IsPatch() {
+		return PatchConfig(sc.ScheduleId, sc.Id, sc.UserMech, sc.UserId, sc.Message, sc.Patch, opts)
+	}
+	return CreateOrUpdateConfig(sc.ScheduleId, sc.Id, sc.Path, sc.UserMech, sc.UserId, sc.Message, sc.Config, opts)
+}
+
+// ApplyDueChanges makes every pending scheduled change due by now, in the order they
+// were scheduled to apply, returning them along with whether each was applied or failed.
+// Only one instance of the service applies changes at a time, as whichever holds the
+// scheduler lock, and each change is marked as applied or failed before it is released,
+// so no change is made twice. Changes which do not break glass are left pending during
+// a freeze window, and applied once it ends.
+func ApplyDueChanges(now time.Time) ([]*ScheduledChange, error) {
+	lock, err := platformsync.RegionLock([]byte(schedulerLockId))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	pending, err := ListScheduledChanges("", true)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*ScheduledChange
+	for _, sc := range pending {
+		if sc.ApplyAt.After(now) {
+			break
+		}
+		// There is nothing before the change if the ID or path does not exist yet
+		sc.PreviousConfig, _, _ = ReadConfig(sc.Id, sc.Path)
+		err := sc.apply()
+		if _, ok := err.(*FrozenError); ok {
+			// Left pending, to apply once the freeze window ends
+			continue
+		}
+		if err != nil {
+			sc.Status = ScheduleFailed
+			sc.Error = err.Error()
+		} else {
+			sc.Status = ScheduleApplied
+			sc.ChangeId = sc.ScheduleId
+		}
+		if err := DefaultRepository.SaveScheduledChange(sc); err != nil {
+			return due, fmt.Errorf("Error saving scheduled change: %v", err)
+		}
+		due = append(due, sc)
+	}
+	return due, nil
+}
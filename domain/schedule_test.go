@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestScheduledChanges() {
+	id := "H2:BASE"
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{"cassandra":{"hosts":["c01"]}}`), Revision: 1, Timestamp: time.Now()},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+	s.zk.
+		On("NewLock", lockPath(schedulerLockId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	now := time.Now()
+	_, err := ScheduleChange("s0", id, "cassandra/port", "h2", "dave", "Too late", []byte(`9160`), nil, now.Add(-time.Minute), nil)
+	s.Equal(ErrScheduleInPast, err)
+	_, err = ScheduleChange("s0", id, "cassandra/port", "h2", "dave", "Bad", []byte(`{`), nil, now.Add(time.Minute), nil)
+	_, ok := err.(*ValidationError)
+	s.True(ok)
+
+	_, err = ScheduleChange("s1", id, "cassandra/hosts", "h2", "dave", "Add c02", []byte(`["c01","c02"]`), nil, now.Add(time.Minute), nil)
+	s.NoError(err)
+	_, err = ScheduleChange("s2", id, "", "h2", "dave", "Add port", nil, []byte(`[{"op":"add","path":"/cassandra/port","value":9160}]`), now.Add(2*time.Minute), nil)
+	s.NoError(err)
+	_, err = ScheduleChange("s3", id, "cassandra/hosts", "h2", "dave", "Drop c01", []byte(`["c02"]`), nil, now.Add(3*time.Minute), nil)
+	s.NoError(err)
+	_, err = ScheduleChange("s4", id, "", "h2", "dave", "Bad test", nil, []byte(`[{"op":"test","path":"/cassandra/port","value":1}]`), now.Add(2*time.Minute), nil)
+	s.NoError(err)
+
+	// Nothing is applied before it is due
+	scs, err := ApplyDueChanges(now)
+	s.NoError(err)
+	s.Len(scs, 0)
+	pending, err := ListScheduledChanges(id, true)
+	s.NoError(err)
+	s.Len(pending, 4)
+	s.Equal("s1", pending[0].ScheduleId)
+
+	_, err = CancelScheduledChange("s3", "h2", "bob", "Not yet")
+	s.NoError(err)
+	_, err = CancelScheduledChange("s3", "h2", "bob", "Not yet")
+	s.Equal(ErrScheduleClosed, err)
+
+	// Due changes are applied in order, as whoever scheduled them, and linked to the change
+	scs, err = ApplyDueChanges(now.Add(5 * time.Minute))
+	s.NoError(err)
+	s.Len(scs, 3)
+	s.Equal("s1", scs[0].ScheduleId)
+	s.Equal(ScheduleApplied, scs[0].Status)
+	s.Equal(`["c01"]`, string(scs[0].PreviousConfig))
+
+	config, cs, err := ReadConfig(id, "")
+	s.NoError(err)
+	s.Equal(`{"cassandra":{"hosts":["c01","c02"],"port":9160}}`, string(config))
+	s.Equal("dave", cs.UserId)
+	s.Equal(int64(3), cs.Revision)
+	_, cs, err = ReadConfigAtRevision(id, "", 2)
+	s.NoError(err)
+	s.Equal("s1", cs.ChangeId)
+	s.Equal("s1", cs.ScheduleId)
+
+	sc, err := ReadScheduledChange("s4")
+	s.NoError(err)
+	s.Equal(ScheduleFailed, sc.Status)
+	s.NotEqual("", sc.Error)
+	sc, err = ReadScheduledChange("s3")
+	s.NoError(err)
+	s.Equal(ScheduleCancelled, sc.Status)
+	s.Equal("bob", sc.CancelledId)
+
+	// Nothing is applied twice
+	scs, err = ApplyDueChanges(now.Add(5 * time.Minute))
+	s.NoError(err)
+	s.Len(scs, 0)
+	all, err := ListScheduledChanges("", false)
+	s.NoError(err)
+	s.Len(all, 4)
+}
+
+func (s *DomainSuite) TestScheduledChangesDuringFreeze() {
+	id := "H2:BASE"
+	now := time.Now().UTC()
+	DefaultRepository = &memoryRepository{
+		data: map[string]*ChangeSet{
+			id: &ChangeSet{Id: id, Body: []byte(`{"foo":1}`), Revision: 1, Timestamp: now},
+		},
+	}
+	s.zk.
+		On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+	s.zk.
+		On("NewLock", lockPath(schedulerLockId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	_, err := ScheduleChange("s1", id, "foo", "h2", "dave", "Launch", []byte(`2`), nil, now.Add(time.Minute), nil)
+	s.NoError(err)
+	_, err = ScheduleChange("s2", id, "bar", "h2", "dave", "Outage fix", []byte(`3`), nil, now.Add(time.Minute), &WriteOptions{BreakGlass: true})
+	s.NoError(err)
+
+	DefaultRepository.(*memoryRepository).data[FreezeId] = &ChangeSet{Id: FreezeId, Timestamp: now, Body: []byte(fmt.Sprintf(
+		`{"windows":[{"start":%q,"end":%q,"reason":"Peak"}]}`,
+		now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339)))}
+
+	// Only the change breaking glass is applied, and the other is left for later
+	scs, err := ApplyDueChanges(now.Add(5 * time.Minute))
+	s.NoError(err)
+	s.Len(scs, 1)
+	s.Equal("s2", scs[0].ScheduleId)
+	s.Equal(ScheduleApplied, scs[0].Status)
+	pending, err := ListScheduledChanges(id, true)
+	s.NoError(err)
+	s.Len(pending, 1)
+	s.Equal("s1", pending[0].ScheduleId)
+
+	// Once the freeze is over it is applied
+	delete(DefaultRepository.(*memoryRepository).data, FreezeId)
+	scs, err = ApplyDueChanges(now.Add(5 * time.Minute))
+	s.NoError(err)
+	s.Len(scs, 1)
+	s.Equal("s1", scs[0].ScheduleId)
+	s.Equal(ScheduleApplied, scs[0].Status)
+	config, _, err := ReadConfig(id, "")
+	s.NoError(err)
+	s.Equal(`{"bar":3,"foo":2}`, string(config))
+}
@@ -0,0 +1,777 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SchemaId is the config ID under which JSON schemas are registered. It is stored
+	// like any other config, so every schema change is versioned and audited.
+	SchemaId = "SCHEMA"
+	// schemaKey marks a node within the schema registry as being the schema for the
+	// config path leading to it, eg: {"hailo":{"service":{"zookeeper":{"_schema":{...}}}}}
+	schemaKey = "_schema"
+	// schemaCacheTTL is how long the schemas used when compiling are cached for. Writes to
+	// the registry through this instance are seen at once, and those through others
+	// within this long.
+	schemaCacheTTL = 30 * time.Second
+)
+
+var (
+	schemaCacheMtx sync.Mutex
+	// cachedSchemas were read from cachedFrom at schemasReadAt
+	cachedSchemas map[string]*Schema
+	cachedFrom    ConfigRepository
+	schemasReadAt time.Time
+)
+
+// Schema is a parsed JSON schema, supporting the commonly used subset of draft 4
+type Schema struct {
+	Types                []string
+	Enum                 []interface{}
+	Properties           map[string]*Schema
+	PatternProperties    map[*regexp.Regexp]*Schema
+	AdditionalProperties *Schema
+	NoAdditional         bool
+	Required             []string
+	Items                *Schema
+	MinItems             *int
+	MaxItems             *int
+	UniqueItems          bool
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     bool
+	ExclusiveMaximum     bool
+	MinLength            *int
+	MaxLength            *int
+	Pattern              *regexp.Regexp
+	AllOf                []*Schema
+	AnyOf                []*Schema
+	OneOf                []*Schema
+	Not                  *Schema
+	// MergeStrategy is the default strategy used to merge an array at this path
+	// when compiling, declared with the "mergeStrategy" and "mergeKey" keywords
+	MergeStrategy *mergeDirective
+}
+
+// Violation describes a single way in which some config does not satisfy a schema
+type Violation struct {
+	// Path is the "/" separated path of the offending value
+	Path string
+	// Message is a human-readable description of the problem
+	Message string
+}
+
+func (v *Violation) Error() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// ValidationResult is the outcome of validating config against the schema registry
+type ValidationResult struct {
+	// Present lists the paths of schemas which had config to validate
+	Present []string
+	// Absent lists the paths of schemas for which no config exists
+	Absent []string
+	// Violations lists every problem found
+	Violations []*Violation
+}
+
+// IsValid returns true if no violations were found
+func (r *ValidationResult) IsValid() bool {
+	return len(r.Violations) == 0
+}
+
+// ParseSchema parses a JSON schema document
+func ParseSchema(data []byte) (*Schema, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("Schema is not valid JSON: %v", err)
+	}
+	return parseSchema(raw)
+}
+
+func parseSchema(raw interface{}) (*Schema, error) {
+	def, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Schema should be a JSON object")
+	}
+
+	s := &Schema{}
+	var err error
+
+	switch t := def["type"].(type) {
+	case nil:
+	case string:
+		s.Types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("Schema type should be a string or array of strings")
+			}
+			s.Types = append(s.Types, str)
+		}
+	default:
+		return nil, fmt.Errorf("Schema type should be a string or array of strings")
+	}
+	for _, t := range s.Types {
+		switch t {
+		case "object", "array", "string", "number", "integer", "boolean", "null":
+		default:
+			return nil, fmt.Errorf("Unknown schema type %q", t)
+		}
+	}
+
+	if enum, ok := def["enum"]; ok {
+		if s.Enum, ok = enum.([]interface{}); !ok {
+			return nil, fmt.Errorf("Schema enum should be an array")
+		}
+	}
+
+	if props, ok := def["properties"]; ok {
+		m, ok := props.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Schema properties should be an object")
+		}
+		s.Properties = make(map[string]*Schema, len(m))
+		for k, v := range m {
+			if s.Properties[k], err = parseSchema(v); err != nil {
+				return nil, fmt.Errorf("Property %q: %v", k, err)
+			}
+		}
+	}
+
+	if props, ok := def["patternProperties"]; ok {
+		m, ok := props.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Schema patternProperties should be an object")
+		}
+		s.PatternProperties = make(map[*regexp.Regexp]*Schema, len(m))
+		for k, v := range m {
+			re, err := regexp.Compile(k)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid pattern %q: %v", k, err)
+			}
+			if s.PatternProperties[re], err = parseSchema(v); err != nil {
+				return nil, fmt.Errorf("Pattern property %q: %v", k, err)
+			}
+		}
+	}
+
+	switch additional := def["additionalProperties"].(type) {
+	case nil:
+	case bool:
+		s.NoAdditional = !additional
+	default:
+		if s.AdditionalProperties, err = parseSchema(additional); err != nil {
+			return nil, fmt.Errorf("additionalProperties: %v", err)
+		}
+	}
+
+	if required, ok := def["required"]; ok {
+		arr, ok := required.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Schema required should be an array of strings")
+		}
+		for _, v := range arr {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("Schema required should be an array of strings")
+			}
+			s.Required = append(s.Required, str)
+		}
+	}
+
+	if items, ok := def["items"]; ok {
+		if s.Items, err = parseSchema(items); err != nil {
+			return nil, fmt.Errorf("items: %v", err)
+		}
+	}
+
+	if s.MinItems, err = schemaInt(def, "minItems"); err != nil {
+		return nil, err
+	}
+	if s.MaxItems, err = schemaInt(def, "maxItems"); err != nil {
+		return nil, err
+	}
+	if s.MinLength, err = schemaInt(def, "minLength"); err != nil {
+		return nil, err
+	}
+	if s.MaxLength, err = schemaInt(def, "maxLength"); err != nil {
+		return nil, err
+	}
+	if s.Minimum, err = schemaNumber(def, "minimum"); err != nil {
+		return nil, err
+	}
+	if s.Maximum, err = schemaNumber(def, "maximum"); err != nil {
+		return nil, err
+	}
+	s.UniqueItems, _ = def["uniqueItems"].(bool)
+	s.ExclusiveMinimum, _ = def["exclusiveMinimum"].(bool)
+	s.ExclusiveMaximum, _ = def["exclusiveMaximum"].(bool)
+
+	if pattern, ok := def["pattern"]; ok {
+		str, ok := pattern.(string)
+		if !ok {
+			return nil, fmt.Errorf("Schema pattern should be a string")
+		}
+		if s.Pattern, err = regexp.Compile(str); err != nil {
+			return nil, fmt.Errorf("Invalid pattern %q: %v", str, err)
+		}
+	}
+
+	if s.AllOf, err = schemaList(def, "allOf"); err != nil {
+		return nil, err
+	}
+	if s.AnyOf, err = schemaList(def, "anyOf"); err != nil {
+		return nil, err
+	}
+	if s.OneOf, err = schemaList(def, "oneOf"); err != nil {
+		return nil, err
+	}
+	if not, ok := def["not"]; ok {
+		if s.Not, err = parseSchema(not); err != nil {
+			return nil, fmt.Errorf("not: %v", err)
+		}
+	}
+
+	if strategy, ok := def["mergeStrategy"]; ok {
+		str, ok := strategy.(string)
+		if !ok {
+			return nil, fmt.Errorf("Schema mergeStrategy should be a string")
+		}
+		key, _ := def["mergeKey"].(string)
+		if s.MergeStrategy, err = parseMergeStrategy(str, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func schemaInt(def map[string]interface{}, key string) (*int, error) {
+	v, ok := def[key]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := v.(float64)
+	if !ok || f != math.Trunc(f) || f < 0 {
+		return nil, fmt.Errorf("Schema %s should be a non-negative integer", key)
+	}
+	i := int(f)
+	return &i, nil
+}
+
+func schemaNumber(def map[string]interface{}, key string) (*float64, error) {
+	v, ok := def[key]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("Schema %s should be a number", key)
+	}
+	return &f, nil
+}
+
+func schemaList(def map[string]interface{}, key string) ([]*Schema, error) {
+	v, ok := def[key]
+	if !ok {
+		return nil, nil
+	}
+	arr, ok := v.([]interface{})
+	if !ok || len(arr) == 0 {
+		return nil, fmt.Errorf("Schema %s should be a non-empty array", key)
+	}
+	schemas := make([]*Schema, len(arr))
+	for i, raw := range arr {
+		s, err := parseSchema(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %v", key, i, err)
+		}
+		schemas[i] = s
+	}
+	return schemas, nil
+}
+
+// jsonType returns the JSON schema type name of a decoded JSON value
+func jsonType(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if t == math.Trunc(t) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "/" + key
+}
+
+// Validate checks the decoded JSON value v against the schema, returning all
+// violations found. Path is used as the prefix for reporting where they occurred.
+func (s *Schema) Validate(v interface{}, path string) []*Violation {
+	return s.validate(v, path, false)
+}
+
+// validate implements Validate. In partial mode required properties are not
+// enforced, since a single ID only holds one layer of the compiled config.
+func (s *Schema) validate(v interface{}, path string, partial bool) []*Violation {
+	if m, ok := v.(map[string]interface{}); ok && isMergeDirective(m) {
+		// Validate the value the directive applies to
+		v = m[mergeValueKey]
+	} else if ok && isDeleteMarker(m) {
+		// Nothing to validate, the compiled config won't have this value
+		return nil
+	}
+
+	var violations []*Violation
+	fail := func(p, format string, args ...interface{}) {
+		violations = append(violations, &Violation{Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+
+	actual := jsonType(v)
+	if len(s.Types) > 0 {
+		matched := false
+		for _, t := range s.Types {
+			if t == actual || (t == "number" && actual == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail(path, "expected %s, got %s", strings.Join(s.Types, " or "), actual)
+			return violations
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b, _ := json.Marshal(s.Enum)
+			fail(path, "value should be one of %s", b)
+		}
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, req := range s.Required {
+			if _, ok := t[req]; !ok && !partial {
+				fail(path, "missing required property %q", req)
+			}
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			matched := false
+			if prop, ok := s.Properties[k]; ok {
+				matched = true
+				violations = append(violations, prop.validate(t[k], joinPath(path, k), partial)...)
+			}
+			for re, prop := range s.PatternProperties {
+				if re.MatchString(k) {
+					matched = true
+					violations = append(violations, prop.validate(t[k], joinPath(path, k), partial)...)
+				}
+			}
+			if matched {
+				continue
+			}
+			if s.NoAdditional {
+				fail(joinPath(path, k), "additional property not allowed")
+			} else if s.AdditionalProperties != nil {
+				violations = append(violations, s.AdditionalProperties.validate(t[k], joinPath(path, k), partial)...)
+			}
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(t) < *s.MinItems {
+			fail(path, "expected at least %d items, got %d", *s.MinItems, len(t))
+		}
+		if s.MaxItems != nil && len(t) > *s.MaxItems {
+			fail(path, "expected at most %d items, got %d", *s.MaxItems, len(t))
+		}
+		if s.UniqueItems {
+			for i := 0; i < len(t); i++ {
+				for j := i + 1; j < len(t); j++ {
+					if reflect.DeepEqual(t[i], t[j]) {
+						fail(path, "items %d and %d are not unique", i, j)
+					}
+				}
+			}
+		}
+		if s.Items != nil {
+			for i, item := range t {
+				violations = append(violations, s.Items.validate(item, joinPath(path, fmt.Sprintf("%d", i)), partial)...)
+			}
+		}
+
+	case string:
+		length := len([]rune(t))
+		if s.MinLength != nil && length < *s.MinLength {
+			fail(path, "expected at least %d characters, got %d", *s.MinLength, length)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail(path, "expected at most %d characters, got %d", *s.MaxLength, length)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(t) {
+			fail(path, "value %q does not match pattern %q", t, s.Pattern.String())
+		}
+
+	case float64:
+		if s.Minimum != nil && (t < *s.Minimum || (s.ExclusiveMinimum && t == *s.Minimum)) {
+			fail(path, "value %v is below the minimum of %v", t, *s.Minimum)
+		}
+		if s.Maximum != nil && (t > *s.Maximum || (s.ExclusiveMaximum && t == *s.Maximum)) {
+			fail(path, "value %v is above the maximum of %v", t, *s.Maximum)
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		violations = append(violations, sub.validate(v, path, partial)...)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if len(sub.validate(v, path, partial)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail(path, "value does not match any of the allowed schemas")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if len(sub.validate(v, path, partial)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail(path, "value matches %d of the allowed schemas, expected exactly one", matched)
+		}
+	}
+	if s.Not != nil && len(s.Not.validate(v, path, partial)) == 0 {
+		fail(path, "value matches a disallowed schema")
+	}
+
+	return violations
+}
+
+// findSchemas walks the schema registry document, collecting the raw schema
+// definitions keyed by the config path they apply to
+func findSchemas(node map[string]interface{}, path string, found map[string]interface{}) {
+	for k, v := range node {
+		if k == schemaKey {
+			found[path] = v
+			continue
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			findSchemas(m, joinPath(path, k), found)
+		}
+	}
+}
+
+// parseSchemaRegistry decodes the body of the schema registry ID
+func parseSchemaRegistry(body []byte) (map[string]*Schema, error) {
+	schemas := make(map[string]*Schema)
+	if len(body) == 0 {
+		return schemas, nil
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, fmt.Errorf("Error decoding schema registry: %v", err)
+	}
+
+	raw := make(map[string]interface{})
+	findSchemas(decoded, "", raw)
+	for path, def := range raw {
+		s, err := parseSchema(def)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid schema for path %q: %v", path, err)
+		}
+		schemas[path] = s
+	}
+	return schemas, nil
+}
+
+// ReadSchemas returns all registered schemas, keyed by the config path they apply to
+func ReadSchemas() (map[string]*Schema, error) {
+	configs, err := DefaultRepository.ReadConfig([]string{SchemaId})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting schemas from DAO: %v", err)
+	}
+	if len(configs) != 1 || configs[0] == nil {
+		return make(map[string]*Schema), nil
+	}
+	return parseSchemaRegistry(configs[0].Body)
+}
+
+// readCachedSchemas is like ReadSchemas, but only reads the registry again once the
+// schemas read last are older than schemaCacheTTL, or have been written since
+func readCachedSchemas() (map[string]*Schema, error) {
+	schemaCacheMtx.Lock()
+	defer schemaCacheMtx.Unlock()
+
+	if cachedSchemas != nil && cachedFrom == DefaultRepository && time.Since(schemasReadAt) < schemaCacheTTL {
+		return cachedSchemas, nil
+	}
+	schemas, err := ReadSchemas()
+	if err != nil {
+		return nil, err
+	}
+	cachedSchemas, cachedFrom, schemasReadAt = schemas, DefaultRepository, time.Now()
+	return schemas, nil
+}
+
+// forgetSchemas clears the cached schemas, once the registry has been written
+func forgetSchemas() {
+	schemaCacheMtx.Lock()
+	defer schemaCacheMtx.Unlock()
+	cachedSchemas = nil
+}
+
+// isWithinPath returns true if path p is equal to, or nested beneath, parent
+func isWithinPath(p, parent string) bool {
+	return parent == "" || p == parent || strings.HasPrefix(p, parent+"/")
+}
+
+// schemasWithin returns the schemas for paths at or beneath path
+func schemasWithin(schemas map[string]*Schema, path string) map[string]*Schema {
+	found := make(map[string]*Schema)
+	for p, s := range schemas {
+		if isWithinPath(p, path) {
+			found[p] = s
+		}
+	}
+	return found
+}
+
+// schemasAffectedBy returns the schemas which could be affected by a change at
+// path, ie: those at or beneath it, and those for any of its parents
+func schemasAffectedBy(schemas map[string]*Schema, path string) map[string]*Schema {
+	found := make(map[string]*Schema)
+	for p, s := range schemas {
+		if isWithinPath(p, path) || isWithinPath(path, p) {
+			found[p] = s
+		}
+	}
+	return found
+}
+
+// validateDocument validates a decoded config document against each of the schemas
+func validateDocument(doc map[string]interface{}, schemas map[string]*Schema, partial bool) *ValidationResult {
+	result := &ValidationResult{
+		Present:    make([]string, 0),
+		Absent:     make([]string, 0),
+		Violations: make([]*Violation, 0),
+	}
+
+	paths := make([]string, 0, len(schemas))
+	for p := range schemas {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		var node interface{} = doc
+		if p != "" {
+			for _, part := range strings.Split(p, "/") {
+				m, ok := node.(map[string]interface{})
+				if !ok {
+					node = nil
+					break
+				}
+				if node, ok = m[part]; !ok {
+					node = nil
+					break
+				}
+			}
+		}
+		if node == nil {
+			result.Absent = append(result.Absent, p)
+			continue
+		}
+		result.Present = append(result.Present, p)
+		result.Violations = append(result.Violations, schemas[p].validate(node, p, partial)...)
+	}
+
+	return result
+}
+
+// ValidateConfig compiles the config for the given ids and validates it against
+// every registered schema at or beneath path
+func ValidateConfig(ids []string, path string) (*ValidationResult, error) {
+	schemas, err := ReadSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := CompileConfig(ids, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(compiled, &doc); err != nil {
+		return nil, fmt.Errorf("Error decoding compiled config: %v", err)
+	}
+
+	return validateDocument(doc, schemasWithin(schemas, path), false), nil
+}
+
+// ValidationError is returned when a change is rejected for being invalid, such as
+// violating registered schemas
+type ValidationError struct {
+	Violations []*Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return fmt.Sprintf("Config is invalid: %s", strings.Join(msgs, "; "))
+}
+
+func inHierarchy(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByHierarchy puts configs into the order of ids, which they must all be within
+func sortByHierarchy(configs []*ChangeSet, ids []string) {
+	position := make(map[string]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+	sort.Sort(byPosition{configs, position})
+}
+
+type byPosition struct {
+	configs  []*ChangeSet
+	position map[string]int
+}
+
+func (b byPosition) Len() int      { return len(b.configs) }
+func (b byPosition) Swap(i, j int) { b.configs[i], b.configs[j] = b.configs[j], b.configs[i] }
+func (b byPosition) Less(i, j int) bool {
+	return b.position[b.configs[i].Id] < b.position[b.configs[j].Id]
+}
+
+// validateChange checks the new body for an ID against the schemas affected by a
+// change at path. If compiled is set, the compiled config of the ID's standard H2
+// hierarchy, with the new body and those of any pending changes to other IDs in
+// place, is validated too.
+func validateChange(id, path string, body []byte, compiled bool, pending map[string][]byte) error {
+	switch id {
+	case SchemaId, HierarchyId, SecretsId, RedactionId, AclId, ApprovalId, FreezeId:
+		return nil
+	}
+
+	schemas, err := ReadSchemas()
+	if err != nil {
+		return err
+	}
+	schemas = schemasAffectedBy(schemas, path)
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("Error decoding config: %v", err)
+	}
+	maskSecrets(doc)
+	violations := validateDocument(doc, schemas, true).Violations
+
+	var ids []string
+	if compiled {
+		if ids, err = hierarchyFor(id); err != nil {
+			return err
+		}
+	}
+	if ids != nil {
+		configs, err := DefaultRepository.ReadConfig(ids)
+		if err != nil {
+			return fmt.Errorf("Error getting configs: %v", err)
+		}
+
+		// Swap in the new versions of the IDs being changed
+		for pendingId, pendingBody := range pending {
+			if pendingId != id && inHierarchy(ids, pendingId) {
+				configs = withBody(configs, pendingId, pendingBody)
+			}
+		}
+		configs = withBody(configs, id, body)
+		sortByHierarchy(configs, ids)
+
+		merged, err := mergeConfigs(configs, false, nil)
+		if ierr, ok := err.(*InterpolationError); ok {
+			return &ValidationError{Violations: []*Violation{{Path: ierr.Path, Message: ierr.Error()}}}
+		}
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool, len(violations))
+		for _, v := range violations {
+			seen[v.Error()] = true
+		}
+		for _, v := range validateDocument(merged, schemas, false).Violations {
+			if seen[v.Error()] {
+				// Already reported against the ID itself
+				continue
+			}
+			v.Message = fmt.Sprintf("%s (compiled from %s)", v.Message, strings.Join(ids, ","))
+			violations = append(violations, v)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"time"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+var schemaRegistry = `{
+  "hailo": {
+    "service": {
+      "zookeeper": {
+        "_schema": {
+          "type": "object",
+          "required": ["hosts"],
+          "properties": {
+            "recvTimeout": {"type": "string", "pattern": "^[0-9]+(ms|s)$"},
+            "hosts": {"type": "array", "minItems": 1, "items": {"type": "string"}}
+          },
+          "additionalProperties": false
+        }
+      },
+      "memcache": {
+        "_schema": {"type": "object"}
+      }
+    }
+  }
+}`
+
+func (s *DomainSuite) TestValidateConfig() {
+	testCases := []struct {
+		config     string
+		path       string
+		present    []string
+		absent     []string
+		violations []string
+	}{
+		// Valid config
+		{`{"hailo":{"service":{"zookeeper":{"recvTimeout":"200ms","hosts":["localhost:2181"]}}}}`, "",
+			[]string{"hailo/service/zookeeper"}, []string{"hailo/service/memcache"}, []string{}},
+		// Wrong types, bad pattern, unknown keys
+		{`{"hailo":{"service":{"zookeeper":{"recvTimeout":200,"hosts":[1],"foo":true}}}}`, "",
+			[]string{"hailo/service/zookeeper"}, []string{"hailo/service/memcache"}, []string{
+				"hailo/service/zookeeper/foo: additional property not allowed",
+				"hailo/service/zookeeper/hosts/0: expected string, got integer",
+				"hailo/service/zookeeper/recvTimeout: expected string, got integer",
+			}},
+		// Missing required and too few items
+		{`{"hailo":{"service":{"zookeeper":{"recvTimeout":"2 seconds"},"memcache":{}}}}`, "",
+			[]string{"hailo/service/memcache", "hailo/service/zookeeper"}, []string{}, []string{
+				`hailo/service/zookeeper: missing required property "hosts"`,
+				`hailo/service/zookeeper/recvTimeout: value "2 seconds" does not match pattern "^[0-9]+(ms|s)$"`,
+			}},
+		// Path restricts which schemas are checked
+		{`{"hailo":{"service":{"zookeeper":{"recvTimeout":200}}}}`, "hailo/service/memcache",
+			[]string{}, []string{"hailo/service/memcache"}, []string{}},
+	}
+
+	for i, tc := range testCases {
+		DefaultRepository = &memoryRepository{
+			data: map[string]*ChangeSet{
+				SchemaId: &ChangeSet{
+					Id:        SchemaId,
+					Body:      []byte(schemaRegistry),
+					Timestamp: time.Now(),
+				},
+				"a": &ChangeSet{
+					Id:        "a",
+					Body:      []byte(tc.config),
+					Timestamp: time.Now(),
+				},
+			},
+		}
+
+		result, err := ValidateConfig([]string{"a"}, tc.path)
+		s.NoError(err)
+
+		violations := make([]string, len(result.Violations))
+		for j, v := range result.Violations {
+			violations[j] = v.Error()
+		}
+		s.Equal(tc.present, result.Present, "Present schemas incorrect for testcase %v", i)
+		s.Equal(tc.absent, result.Absent, "Absent schemas incorrect for testcase %v", i)
+		s.Equal(tc.violations, violations, "Violations incorrect for testcase %v", i)
+		s.Equal(len(tc.violations) == 0, result.IsValid())
+	}
+}
+
+func (s *DomainSuite) TestUpdateSchemaRegistry() {
+	testRepo := &memoryRepository{
+		data: map[string]*ChangeSet{},
+	}
+	DefaultRepository = testRepo
+
+	s.zk.
+		On("NewLock", lockPath(SchemaId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	// Invalid schemas are rejected
+	err := CreateOrUpdateConfig("foo", SchemaId, "hailo/_schema", "h2", "dave", "Test Message", []byte(`{"type":"thing"}`), nil)
+	s.Error(err)
+	_, ok := testRepo.data[SchemaId]
+	s.False(ok)
+
+	err = CreateOrUpdateConfig("foo", SchemaId, "hailo/_schema", "h2", "dave", "Test Message", []byte(`{"type":"object"}`), nil)
+	s.NoError(err)
+
+	schemas, err := ReadSchemas()
+	s.NoError(err)
+	s.Len(schemas, 1)
+	s.NotNil(schemas["hailo"])
+}
+
+func (s *DomainSuite) TestUpdateValidatesAgainstSchemas() {
+	id := "H2:REGION:eu-west-1"
+	testCases := []struct {
+		base             string
+		path             string
+		value            string
+		skipValidation   bool
+		validateCompiled bool
+		shouldError      bool
+	}{
+		// Partial config is fine for a single ID, required properties are not enforced
+		{`{}`, "hailo/service/zookeeper/recvTimeout", `"200ms"`, false, false, false},
+		// Wrong type
+		{`{}`, "hailo/service/zookeeper/recvTimeout", `200`, false, false, true},
+		// Wrong type, forced through
+		{`{}`, "hailo/service/zookeeper/recvTimeout", `200`, true, false, false},
+		// Unrelated paths are not checked
+		{`{}`, "hailo/service/foo", `200`, false, false, false},
+		// Replacing a parent of the schema path is checked
+		{`{}`, "hailo/service", `{"zookeeper":{"foo":"bar"}}`, false, false, true},
+		// Compiled config is missing the required hosts
+		{`{}`, "hailo/service/zookeeper/recvTimeout", `"200ms"`, false, true, true},
+		// Compiled config has hosts from the base
+		{`{"hailo":{"service":{"zookeeper":{"hosts":["zk01"]}}}}`, "hailo/service/zookeeper/recvTimeout", `"200ms"`, false, true, false},
+	}
+
+	for i, tc := range testCases {
+		testRepo := &memoryRepository{
+			data: map[string]*ChangeSet{
+				SchemaId: &ChangeSet{
+					Id:        SchemaId,
+					Body:      []byte(schemaRegistry),
+					Timestamp: time.Now(),
+				},
+				"H2:BASE": &ChangeSet{
+					Id:        "H2:BASE",
+					Body:      []byte(tc.base),
+					Timestamp: time.Now(),
+				},
+			},
+		}
+		DefaultRepository = testRepo
+
+		s.zk.
+			On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+
+		err := CreateOrUpdateConfig("foo", id, tc.path, "h2", "dave", "Test Message", []byte(tc.value), &WriteOptions{
+			SkipValidation:   tc.skipValidation,
+			ValidateCompiled: tc.validateCompiled,
+		})
+		if tc.shouldError {
+			_, ok := err.(*ValidationError)
+			s.True(ok, "Expected validation error for testcase %v, got %v", i, err)
+			_, ok = testRepo.data[id]
+			s.False(ok, "Invalid config was saved for testcase %v", i)
+			continue
+		}
+		s.NoError(err, "Unexpected error for testcase %v", i)
+		s.Equal(tc.skipValidation, testRepo.data[id].SkipValidation)
+	}
+}
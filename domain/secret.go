@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// SecretsId is the config ID under which secrets are stored, encrypted, keyed by
+	// their "/" separated name
+	SecretsId = "SECRETS"
+	// secretKey marks an object within a layer of config as a reference to a secret,
+	// which is only revealed when compiling: {"$secret": "monitoring/apiPassword"}
+	secretKey = "$secret"
+	// MaskedSecret is shown in place of a secret's value
+	MaskedSecret = "******"
+)
+
+var (
+	// DefaultKeyProvider supplies the key secrets are encrypted with
+	DefaultKeyProvider KeyProvider
+
+	ErrNoKeyProvider = errors.New("No key provider is configured for secrets")
+)
+
+// KeyProvider supplies the key used to encrypt and decrypt secrets
+type KeyProvider interface {
+	// Key returns the 32 byte AES-256 key
+	Key() ([]byte, error)
+}
+
+// KeyfileProvider reads the key, hex encoded, from a local file
+type KeyfileProvider struct {
+	Path string
+}
+
+func (p *KeyfileProvider) Key() ([]byte, error) {
+	b, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading keyfile: %v", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding keyfile: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("Key should be 32 bytes, not %d", len(key))
+	}
+	return key, nil
+}
+
+// SecretError is returned when a secret referred to by config cannot be revealed
+type SecretError struct {
+	Name    string
+	Message string
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("Cannot reveal secret %q: %s", e.Name, e.Message)
+}
+
+// isSecretRef returns true if the object is a reference to a secret rather than config
+func isSecretRef(m map[string]interface{}) bool {
+	_, ok := m[secretKey]
+	return ok
+}
+
+// checkSecretRef makes sure a reference to a secret is exactly {"$secret": "<name>"}
+func checkSecretRef(m map[string]interface{}) error {
+	if name, ok := m[secretKey].(string); !ok || name == "" || len(m) != 1 {
+		return fmt.Errorf(`Secret reference should be exactly {"%s": "<name>"}`, secretKey)
+	}
+	return nil
+}
+
+func secretCipher() (cipher.AEAD, error) {
+	if DefaultKeyProvider == nil {
+		return nil, ErrNoKeyProvider
+	}
+	key, err := DefaultKeyProvider.Key()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret returns the base64 encoded nonce and ciphertext for plaintext
+func encryptSecret(plaintext []byte) (string, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("Error generating nonce: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// decryptSecret reverses encryptSecret
+func decryptSecret(encrypted string) ([]byte, error) {
+	gcm, err := secretCipher()
+	if err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(b) < gcm.NonceSize() {
+		return nil, fmt.Errorf("Secret is not validly encrypted")
+	}
+	return gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+}
+
+// SetSecret encrypts value and stores it under name, eg: "monitoring/apiPassword", for
+// config to refer to as {"$secret": "monitoring/apiPassword"}. Only the encrypted value
+// is ever stored or audited.
+func SetSecret(changeId, name, userMech, userId, message, value string) error {
+	if name == "" {
+		return &ValidationError{Violations: []*Violation{{Message: "Secret name is required"}}}
+	}
+	encrypted, err := encryptSecret([]byte(value))
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(encrypted)
+	if err != nil {
+		return fmt.Errorf("Error encoding secret: %v", err)
+	}
+	return CreateOrUpdateConfig(changeId, SecretsId, name, userMech, userId, message, b, &WriteOptions{setSecret: true})
+}
+
+// checkSecretsWrite makes sure that secrets are only written by SetSecret, so that they
+// are always encrypted
+func checkSecretsWrite(id string, opts *WriteOptions) error {
+	if id != SecretsId || (opts != nil && opts.setSecret) {
+		return nil
+	}
+	return &ValidationError{Violations: []*Violation{{Message: "Secrets can only be changed with setsecret"}}}
+}
+
+// resolveSecrets replaces every reference to a secret within node with its value, if
+// reveal is set, or MaskedSecret otherwise. It returns the resulting node.
+func resolveSecrets(node interface{}, reveal bool) (interface{}, error) {
+	var secrets []byte
+	var resolve func(node interface{}) (interface{}, error)
+	resolve = func(node interface{}) (interface{}, error) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if !isSecretRef(n) {
+				for k, v := range n {
+					resolved, err := resolve(v)
+					if err != nil {
+						return nil, err
+					}
+					n[k] = resolved
+				}
+				return n, nil
+			}
+			if !reveal {
+				return MaskedSecret, nil
+			}
+			name, _ := n[secretKey].(string)
+			if secrets == nil {
+				configs, err := DefaultRepository.ReadConfig([]string{SecretsId})
+				if err != nil {
+					return nil, fmt.Errorf("Error getting secrets from DAO: %v", err)
+				}
+				secrets = emptyConfig
+				if len(configs) == 1 {
+					secrets = configs[0].Body
+				}
+			}
+			b, err := readConfigAtPath(secrets, name)
+			var encrypted string
+			if err == nil {
+				err = json.Unmarshal(b, &encrypted)
+			}
+			if err != nil {
+				return nil, &SecretError{Name: name, Message: "secret not found"}
+			}
+			value, err := decryptSecret(encrypted)
+			if err != nil {
+				return nil, &SecretError{Name: name, Message: err.Error()}
+			}
+			return string(value), nil
+		case []interface{}:
+			for i, v := range n {
+				resolved, err := resolve(v)
+				if err != nil {
+					return nil, err
+				}
+				n[i] = resolved
+			}
+		}
+		return node, nil
+	}
+	return resolve(node)
+}
+
+// maskSecrets replaces every reference to a secret within node with MaskedSecret
+func maskSecrets(node interface{}) interface{} {
+	masked, _ := resolveSecrets(node, false)
+	return masked
+}
+
+// MaskSecretsConfig returns body, the config for id, with the encrypted values hidden
+// if id is where secrets are stored
+func MaskSecretsConfig(id string, body []byte) []byte {
+	if id != SecretsId || len(body) == 0 {
+		return body
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return []byte(fmt.Sprintf("%q", MaskedSecret))
+	}
+	var mask func(node interface{}) interface{}
+	mask = func(node interface{}) interface{} {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for k, v := range n {
+				n[k] = mask(v)
+			}
+			return n
+		case []interface{}:
+			for i, v := range n {
+				n[i] = mask(v)
+			}
+			return n
+		case string:
+			return MaskedSecret
+		}
+		return node
+	}
+	b, _ := json.Marshal(mask(decoded))
+	return b
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+)
+
+func (s *DomainSuite) TestSecrets() {
+	keyfile, err := ioutil.TempFile("", "secrets")
+	s.NoError(err)
+	defer os.Remove(keyfile.Name())
+	_, err = keyfile.WriteString(hex.EncodeToString([]byte("0123456789abcdef0123456789abcdef")) + "\n")
+	s.NoError(err)
+	keyfile.Close()
+
+	DefaultKeyProvider = &KeyfileProvider{Path: keyfile.Name()}
+	defer func() { DefaultKeyProvider = nil }()
+
+	DefaultRepository = &memoryRepository{data: map[string]*ChangeSet{}}
+	for _, id := range []string{SecretsId, "a"} {
+		s.zk.
+			On("NewLock", lockPath(id), gozk.WorldACL(gozk.PermAll)).
+			Return(&mockLock{})
+	}
+
+	s.NoError(SetSecret("c1", "monitoring/apiPassword", "h2", "dave", "Password", "hunter2"))
+
+	// Only the encrypted value is stored
+	stored, _, err := ReadConfig(SecretsId, "")
+	s.NoError(err)
+	s.False(strings.Contains(string(stored), "hunter2"), "Secret stored in plaintext: %s", stored)
+	s.Equal(`{"monitoring":{"apiPassword":"******"}}`, string(MaskSecretsConfig(SecretsId, stored)))
+	s.Equal(string(stored), string(MaskSecretsConfig("a", stored)))
+
+	s.NoError(CreateOrUpdateConfig("c2", "a", "", "h2", "dave", "Monitoring",
+		[]byte(`{"monitoring":{"apiUser":"hailo","apiPassword":{"$secret":"monitoring/apiPassword"}}}`), nil))
+
+	// Masked unless revealed
+	compiled, _, err := CompileConfigWithOptions([]string{"a"}, "monitoring", nil)
+	s.NoError(err)
+	eq, err := compareJson([]byte(`{"apiUser":"hailo","apiPassword":"******"}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config not masked: %s", compiled)
+
+	compiled, _, err = CompileConfigWithOptions([]string{"a"}, "monitoring", &CompileOptions{RevealSecrets: true})
+	s.NoError(err)
+	eq, err = compareJson([]byte(`{"apiUser":"hailo","apiPassword":"hunter2"}`), compiled)
+	s.NoError(err)
+	s.True(eq, "Compiled config not revealed: %s", compiled)
+
+	// Missing secrets fail only when revealed
+	s.NoError(CreateOrUpdateConfig("c3", "a", "monitoring/apiKey", "h2", "dave", "Key",
+		[]byte(`{"$secret":"monitoring/apiKey"}`), nil))
+	_, _, err = CompileConfigWithOptions([]string{"a"}, "", &CompileOptions{RevealSecrets: true})
+	serr, ok := err.(*SecretError)
+	s.True(ok, "Expected secret error, got %v", err)
+	if ok {
+		s.Equal("monitoring/apiKey", serr.Name)
+	}
+
+	// References must name a secret
+	err = CreateOrUpdateConfig("c4", "a", "monitoring/apiKey", "h2", "dave", "Key",
+		[]byte(`{"$secret":3}`), nil)
+	_, ok = err.(*ValidationError)
+	s.True(ok, "Expected validation error, got %v", err)
+}
+
+func (s *DomainSuite) TestSecretsOnlyWrittenBySetSecret() {
+	DefaultRepository = &memoryRepository{data: map[string]*ChangeSet{
+		SecretsId: &ChangeSet{Id: SecretsId, Body: []byte(`{"monitoring":{"apiPassword":"encrypted"}}`), Revision: 1},
+	}}
+	s.zk.
+		On("NewLock", lockPath(SecretsId), gozk.WorldACL(gozk.PermAll)).
+		Return(&mockLock{})
+
+	errs := []error{
+		CreateOrUpdateConfig("c1", SecretsId, "monitoring/apiPassword", "h2", "dave", "Plaintext", []byte(`"hunter2"`), nil),
+		DeleteConfig("c2", SecretsId, "monitoring/apiPassword", "h2", "dave", "Delete", nil),
+		PatchConfig("c3", SecretsId, "h2", "dave", "Patch", []byte(`[{"op":"remove","path":"/monitoring"}]`), nil),
+	}
+	_, err := BatchUpdateConfig("b1", "h2", "dave", "Batch", []*BatchUpdate{
+		{ChangeId: "c4", Id: SecretsId, Path: "monitoring/apiPassword", Config: []byte(`"hunter2"`)},
+	})
+	if berr, ok := err.(*BatchError); ok {
+		err = berr.Err
+	}
+	errs = append(errs, err)
+	_, err = DeleteId("c5", SecretsId, "h2", "dave", "Delete", nil)
+	errs = append(errs, err)
+
+	for i, err := range errs {
+		_, ok := err.(*ValidationError)
+		s.True(ok, "Expected validation error for write %v, got %v", i, err)
+	}
+	stored, _, err := ReadConfig(SecretsId, "monitoring/apiPassword")
+	s.NoError(err)
+	s.Equal(`"encrypted"`, string(stored))
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/config-service/domain"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// checkAccess makes sure the caller may change each of paths within id, according to the
+// stored ACL. Denials are audited to the platform event stream along with the reason.
+func checkAccess(req *server.Request, endpoint, changeId, id string, paths []string, mech, user string) errors.Error {
+	err := domain.CheckAccess(id, paths, user, req.Auth().HasAccess)
+	if derr, ok := err.(*domain.AccessDeniedError); ok {
+		log.Warnf("Denied %s of %s by %s:%s: %v", endpoint, id, mech, user, derr)
+		pubNSQEvent("ACCESS_DENIED", changeId, id, derr.Path, mech, user, derr.Error(), "", "", false)
+		return errors.Forbidden(fmt.Sprintf("com.HailoOSS.service.config.%s.denied", endpoint), derr.Error())
+	}
+	if err != nil {
+		return errors.InternalServerError(fmt.Sprintf("com.HailoOSS.service.config.%s", endpoint), fmt.Sprintf("%v", err))
+	}
+	return nil
+}
+
+// checkApproval makes sure id may be changed directly, rather than only by an approved
+// proposal
+func checkApproval(endpoint, id string) errors.Error {
+	required, err := domain.RequiresApproval(id)
+	if err != nil {
+		return errors.InternalServerError(fmt.Sprintf("com.HailoOSS.service.config.%s", endpoint), fmt.Sprintf("%v", err))
+	}
+	if required {
+		return errors.Forbidden(fmt.Sprintf("com.HailoOSS.service.config.%s.approvalrequired", endpoint),
+			fmt.Sprintf("Changes to %q must be proposed and approved", id))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	approve "github.com/HailoOSS/config-service/proto/approve"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Approve applies a pending proposal, which must be reviewed by someone other than the
+// proposer, and fails if the config it changes has changed since it was proposed
+func Approve(req *server.Request) (proto.Message, errors.Error) {
+	request := &approve.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.approve", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	p, err := domain.ReadProposal(request.GetProposalId())
+	if err == domain.ErrProposalNotFound {
+		return nil, errors.NotFound("com.HailoOSS.service.config.approve", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.approve", fmt.Sprintf("%v", err))
+	}
+
+	// The reviewer must be allowed to make the change too
+	if err := checkAccess(req, "approve", p.ProposalId, p.Change.Id, []string{p.Change.Path}, mech, id); err != nil {
+		return nil, err
+	}
+
+	p, err = domain.ApproveProposal(request.GetProposalId(), mech, id, request.GetMessage(), request.GetBreakGlass())
+	if err == domain.ErrProposalStale {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.approve.stale", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrProposalClosed {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.approve.closed", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrSelfReview {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.approve.self", fmt.Sprintf("%v", err))
+	}
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.approve.invalid", verr.Error())
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.approve.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.approve.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.approve", fmt.Sprintf("%v", err))
+	}
+
+	broadcastChange(p.Change.Id)
+
+	// Pub the change to the platform event stream, as made by the reviewer
+	config, _ := p.ChangedConfig()
+	pubNSQEvent("APPROVED", p.Change.ChangeId, p.Change.Id, p.Change.Path, mech, id, request.GetMessage(),
+		string(config), string(p.Change.OldConfig), p.Change.BreakGlass)
+
+	return &approve.Response{
+		ChangeId: proto.String(p.Change.ChangeId),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	batchupdate "github.com/HailoOSS/config-service/proto/batchupdate"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// BatchUpdate applies several updates, possibly to different IDs, all-or-nothing, as one
+// batch of changes which triggers a single reload
+func BatchUpdate(req *server.Request) (proto.Message, errors.Error) {
+	request := &batchupdate.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate", fmt.Sprintf("%v", err))
+	}
+	if len(request.GetUpdate()) == 0 {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate", "At least one update is required")
+	}
+
+	batchId, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.batchupdate.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	updates := make([]*domain.BatchUpdate, len(request.GetUpdate()))
+	for i, u := range request.GetUpdate() {
+		u4, err := gouuid.NewV4()
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.batchupdate.genid", fmt.Sprintf("%v", err))
+		}
+		if err := checkAccess(req, "batchupdate", u4.String(), u.GetId(), []string{u.GetPath()}, mech, id); err != nil {
+			return nil, err
+		}
+		if err := checkApproval("batchupdate", u.GetId()); err != nil {
+			return nil, err
+		}
+		updates[i] = &domain.BatchUpdate{
+			ChangeId: u4.String(),
+			Id:       u.GetId(),
+			Path:     u.GetPath(),
+			Config:   []byte(u.GetConfig()),
+			Opts: &domain.WriteOptions{
+				SkipValidation:   request.GetSkipValidation(),
+				ValidateCompiled: request.GetValidateCompiled(),
+				ExpectedHash:     u.GetExpectedHash(),
+				ExpectedRevision: u.GetExpectedRevision(),
+				Merge:            u.GetMerge(),
+				BreakGlass:       request.GetBreakGlass(),
+				NoReload:         request.GetNoReload(),
+			},
+		}
+	}
+
+	changes, err := domain.BatchUpdateConfig(batchId.String(), mech, id, request.GetMessage(), updates)
+	if berr, ok := err.(*domain.BatchError); ok {
+		if _, ok := berr.Err.(*domain.ValidationError); ok {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate.invalid", berr.Error())
+		}
+		if berr.Err == domain.ErrConfigChanged {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate.stale", berr.Error())
+		}
+		if _, ok := berr.Err.(*domain.ErrPathConflict); ok {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate.conflict", berr.Error())
+		}
+		if _, ok := berr.Err.(*domain.FrozenError); ok {
+			return nil, errors.Forbidden("com.HailoOSS.service.config.batchupdate.frozen", berr.Error())
+		}
+		if berr.Err == domain.ErrBreakGlassMessage {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate.breakglass", berr.Error())
+		}
+		return nil, errors.BadRequest("com.HailoOSS.service.config.batchupdate", berr.Error())
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.batchupdate", fmt.Sprintf("%v", err))
+	}
+
+	rsp := &batchupdate.Response{
+		BatchId:  proto.String(batchId.String()),
+		ChangeId: make([]string, len(changes)),
+	}
+	var ids []string
+	seen := make(map[string]bool, len(changes))
+	for i, cs := range changes {
+		rsp.ChangeId[i] = cs.ChangeId
+		if !seen[cs.Id] {
+			seen[cs.Id] = true
+			ids = append(ids, cs.Id)
+		}
+	}
+
+	if !request.GetNoReload() {
+		broadcastChange(strings.Join(ids, ","))
+
+		// Pub the whole batch to the platform event stream as one event
+		pubEvent(batchToNSQ(batchId.String(), ids, mech, id, request.GetMessage(), request.GetUpdate(), request.GetBreakGlass()))
+	}
+
+	return rsp, nil
+}
@@ -2,23 +2,36 @@ package handler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/HailoOSS/protobuf/proto"
 
 	"github.com/HailoOSS/config-service/domain"
+	common "github.com/HailoOSS/config-service/proto"
 	compile "github.com/HailoOSS/config-service/proto/compile"
 	"github.com/HailoOSS/platform/errors"
 	"github.com/HailoOSS/platform/server"
 )
 
-// Compile constructs a single, merged, view of config, combining many individual elements
+// secretsRole is needed to reveal secrets when compiling
+const secretsRole = "CONFIG.SECRETS"
+
+// Compile constructs a single, merged, view of config, combining many individual elements.
+// An asOf time compiles each element as it was at that point, eg: when investigating an incident.
 func Compile(req *server.Request) (proto.Message, errors.Error) {
 	request := &compile.Request{}
 	if err := req.Unmarshal(request); err != nil {
 		return nil, errors.BadRequest("com.HailoOSS.service.config.compile", fmt.Sprintf("%v", err))
 	}
+	if request.GetRevealSecrets() && !req.Auth().HasAccess(secretsRole) {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.compile.secrets", "Not authorised to reveal secrets")
+	}
 
-	cfg, hash, err := DoCompile(request.GetId(), request.GetPath())
+	cfg, hash, layers, err := DoCompile(request.GetId(), request.GetPath(), &domain.CompileOptions{
+		Strict:        request.GetStrict(),
+		AsOf:          protoToTime(request.AsOf, time.Time{}),
+		RevealSecrets: request.GetRevealSecrets(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -26,14 +39,28 @@ func Compile(req *server.Request) (proto.Message, errors.Error) {
 	return &compile.Response{
 		Config: proto.String(cfg),
 		Hash:   proto.String(hash),
+		Layer:  layers,
 	}, nil
 }
 
 // DoCompile does the real work for compile - and is implemented like this because we want
-// an HTTP interface in addition to the platform interface
-func DoCompile(ids []string, path string) (config, hash string, compileErr errors.Error) {
-	cfg, err := domain.CompileConfig(ids, path)
-	if err == domain.ErrPathNotFound {
+// an HTTP interface in addition to the platform interface. Along with the config, it returns
+// the change id and revision of each layer that was merged.
+func DoCompile(ids []string, path string, opts *domain.CompileOptions) (config, hash string, layers []*common.Layer, compileErr errors.Error) {
+	cfg, configs, err := domain.CompileConfigWithOptions(ids, path, opts)
+	if cerr, ok := err.(*domain.ErrPathConflict); ok {
+		compileErr = errors.BadRequest("com.HailoOSS.service.config.compile.conflict", cerr.Error())
+		return
+	}
+	if ierr, ok := err.(*domain.InterpolationError); ok {
+		compileErr = errors.BadRequest("com.HailoOSS.service.config.compile.interpolation", ierr.Error())
+		return
+	}
+	if serr, ok := err.(*domain.SecretError); ok {
+		compileErr = errors.BadRequest("com.HailoOSS.service.config.compile.secret", serr.Error())
+		return
+	}
+	if err == domain.ErrPathNotFound || err == domain.ErrRevisionNotFound {
 		compileErr = errors.NotFound("com.HailoOSS.service.config.compile", fmt.Sprintf("%v", err))
 		return
 	}
@@ -44,6 +71,7 @@ func DoCompile(ids []string, path string) (config, hash string, compileErr error
 
 	config = string(cfg)
 	hash = createConfigHash(cfg)
+	layers = changesToLayers(configs)
 
 	return
 }
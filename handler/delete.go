@@ -30,17 +30,55 @@ func Delete(req *server.Request) (proto.Message, errors.Error) {
 		return nil, errors.InternalServerError("com.HailoOSS.service.config.delete.genid", fmt.Sprintf("%v", err))
 	}
 
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	if err := checkAccess(req, "delete", u4.String(), request.GetId(), []string{request.GetPath()}, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("delete", request.GetId()); err != nil {
+		return nil, err
+	}
+
 	err = domain.DeleteConfig(
 		u4.String(),
 		request.GetId(),
 		request.GetPath(),
-		req.Auth().AuthUser().Mech,
-		req.Auth().AuthUser().Id,
+		mech,
+		id,
 		request.GetMessage(),
+		&domain.WriteOptions{
+			SkipValidation:   request.GetSkipValidation(),
+			ValidateCompiled: request.GetValidateCompiled(),
+			ExpectedHash:     request.GetExpectedHash(),
+			ExpectedRevision: request.GetExpectedRevision(),
+			BreakGlass:       request.GetBreakGlass(),
+		},
 	)
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.delete.invalid", verr.Error())
+	}
+	if err == domain.ErrConfigChanged {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.delete.stale", fmt.Sprintf("%v", err))
+	}
+	if cerr, ok := err.(*domain.ErrPathConflict); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.delete.conflict", cerr.Error())
+	}
 	if err == domain.ErrPathNotFound {
 		return nil, errors.NotFound("com.HailoOSS.service.config.delete", fmt.Sprintf("%v", err))
 	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.delete.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.delete.breakglass", fmt.Sprintf("%v", err))
+	}
 	if err != nil {
 		return nil, errors.InternalServerError("com.HailoOSS.service.config.delete", fmt.Sprintf("%v", err))
 	}
@@ -48,8 +86,7 @@ func Delete(req *server.Request) (proto.Message, errors.Error) {
 	broadcastChange(request.GetId())
 
 	// Pub the change to the platform event stream
-	pubNSQEvent("DELETED", u4.String(), request.GetId(), request.GetPath(), req.Auth().AuthUser().Mech,
-		req.Auth().AuthUser().Id, request.GetMessage(), "", string(previousConfig))
+	pubNSQEvent("DELETED", u4.String(), request.GetId(), request.GetPath(), mech, id, request.GetMessage(), "", string(previousConfig), request.GetBreakGlass())
 
 	return &del.Response{}, nil
 }
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/HailoOSS/config-service/domain"
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+	"github.com/HailoOSS/protobuf/proto"
+	zk "github.com/HailoOSS/service/zookeeper"
+
+	dproto "github.com/HailoOSS/config-service/proto/delete"
+)
+
+// TestDeleteHandlerS2S checks that deletes from services, which have no authenticated
+// user, are made as the calling service
+func (s *UpdateSuite) TestDeleteHandlerS2S() {
+	id := "H2:REGION:eu-west-1"
+	domain.DefaultRepository = domain.NewMemoryRepository(map[string]*domain.ChangeSet{
+		id: &domain.ChangeSet{
+			Id:        id,
+			Body:      []byte(`{"hailo":{"service":{"memcache":{"hosts":["10.0.0.1"]}}}}`),
+			Timestamp: time.Now(),
+		},
+	})
+
+	lock := &zk.MockLock{}
+	lock.On("Lock").Return(nil)
+	lock.On("Unlock").Return(nil)
+	lock.On("SetTTL", mock.AnythingOfType("time.Duration")).Return()
+	lock.On("SetTimeout", mock.AnythingOfType("time.Duration")).Return()
+
+	lockPath := fmt.Sprintf("/com.HailoOSS.service.config/%s", id)
+	s.zk.
+		On("NewLock", lockPath, gozk.WorldACL(gozk.PermAll)).
+		Return(lock)
+	s.zk.On("Exists", lockPath).Return(false, &gozk.Stat{}, nil)
+	s.zk.On("Delete", lockPath, int32(-1)).Return(nil)
+
+	s.nsq.On("Publish", broadcastTopic, mock.Anything).Return(nil)
+	s.nsq.On("Publish", platformTopicName, mock.Anything).Return(nil)
+
+	_, err := Delete(newTestRequest(&dproto.Request{
+		Id:      proto.String(id),
+		Path:    proto.String("hailo/service/memcache/hosts"),
+		Message: proto.String("Delete test"),
+	}))
+	s.NoError(err)
+
+	_, cs, rerr := domain.ReadConfig(id, "")
+	s.NoError(rerr)
+	s.Equal(defaultMech, cs.UserMech)
+	s.Equal("test", cs.UserId)
+}
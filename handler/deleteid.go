@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	deleteid "github.com/HailoOSS/config-service/proto/deleteid"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// DeleteId deletes an entire config ID, which can later be restored with Undelete
+func DeleteId(req *server.Request) (proto.Message, errors.Error) {
+	request := &deleteid.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.deleteid", fmt.Sprintf("%v", err))
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.deleteid.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	if err := checkAccess(req, "deleteid", u4.String(), request.GetId(), []string{""}, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("deleteid", request.GetId()); err != nil {
+		return nil, err
+	}
+
+	cs, err := domain.DeleteId(
+		u4.String(),
+		request.GetId(),
+		mech,
+		id,
+		request.GetMessage(),
+		&domain.WriteOptions{
+			ExpectedHash:     request.GetExpectedHash(),
+			ExpectedRevision: request.GetExpectedRevision(),
+			BreakGlass:       request.GetBreakGlass(),
+		},
+	)
+	if err == domain.ErrIdNotFound {
+		return nil, errors.NotFound("com.HailoOSS.service.config.deleteid", fmt.Sprintf("%v", err))
+	}
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.deleteid.invalid", verr.Error())
+	}
+	if err == domain.ErrConfigChanged {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.deleteid.stale", fmt.Sprintf("%v", err))
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.deleteid.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.deleteid.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.deleteid", fmt.Sprintf("%v", err))
+	}
+
+	broadcastChange(request.GetId())
+
+	// Pub the change to the platform event stream
+	pubNSQEvent("DELETED_ID", u4.String(), request.GetId(), "", mech, id, request.GetMessage(), "", string(cs.OldConfig), cs.BreakGlass)
+
+	return &deleteid.Response{
+		Revision: proto.Int64(cs.Revision),
+	}, nil
+}
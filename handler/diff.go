@@ -34,7 +34,7 @@ func pretty(text interface{}) (string, error) {
 		return "", nil
 	}
 
-	var m map[string]interface{}
+	var m interface{}
 	err := json.Unmarshal(v, &m)
 	if err != nil {
 		return "", err
@@ -48,8 +48,34 @@ func pretty(text interface{}) (string, error) {
 	return string(b), nil
 }
 
+// diffConfigs returns the differences between two configs, encoded as JSON, along with
+// a GNU style patch
+func diffConfigs(config, newConfig []byte) (string, string, error) {
+	p1, err := pretty(config)
+	if err != nil {
+		return "", "", fmt.Errorf("Error parsing existing config: %v", err)
+	}
+
+	p2, err := pretty(newConfig)
+	if err != nil {
+		return "", "", fmt.Errorf("Error parsing new config: %v", err)
+	}
+
+	deef := differ.DiffMain(p1, p2, true)
+	deef = differ.DiffCleanupSemantic(deef)
+	patch := differ.PatchToText(differ.PatchMake(deef))
+
+	mdiff, err := json.Marshal(deef)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to create response: %v", err)
+	}
+
+	return string(mdiff), patch, nil
+}
+
 // Diff will provide a GNU style diff for a configuration at this level in the path with the supplied
-// config (for the given ID).
+// config (for the given ID). If compile IDs are given, it instead diffs the compiled config of those
+// IDs before and after the change, showing its real effect, eg: of merge directives and deletion markers.
 func Diff(req *server.Request) (proto.Message, errors.Error) {
 	request := &diff.Request{}
 	if err := req.Unmarshal(request); err != nil {
@@ -60,32 +86,38 @@ func Diff(req *server.Request) (proto.Message, errors.Error) {
 		return nil, errors.BadRequest("com.HailoOSS.service.config.diff", "Config cannot be blank")
 	}
 
-	config, _, err := domain.ReadConfig(request.GetId(), request.GetPath())
-	if err != nil && err != domain.ErrPathNotFound {
-		return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("%v", err))
-	}
-
-	p1, err := pretty(config)
-	if err != nil {
-		return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("Error parsing existing config: %v", err))
+	var config, newConfig []byte
+	var err error
+	if ids := request.GetCompileId(); len(ids) > 0 {
+		config, newConfig, err = domain.CompileConfigWithChange(ids, request.GetId(), request.GetPath(), []byte(request.GetConfig()))
+		if cerr, ok := err.(*domain.ErrPathConflict); ok {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.diff.conflict", cerr.Error())
+		}
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("%v", err))
+		}
+	} else {
+		config, _, err = domain.ReadConfig(request.GetId(), request.GetPath())
+		if err != nil && err != domain.ErrPathNotFound {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("%v", err))
+		}
+		newConfig = []byte(request.GetConfig())
 	}
 
-	p2, err := pretty(request.GetConfig())
-	if err != nil {
-		return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("Error parsing new config: %v", err))
-	}
+	config = domain.MaskSecretsConfig(request.GetId(), config)
+	newConfig = domain.MaskSecretsConfig(request.GetId(), newConfig)
 
-	deef := differ.DiffMain(p1, p2, true)
-	deef = differ.DiffCleanupSemantic(deef)
-	patch := differ.PatchToText(differ.PatchMake(deef))
+	policy := redactionPolicy()
+	config = policy.Redact(request.GetId(), request.GetPath(), config)
+	newConfig = policy.Redact(request.GetId(), request.GetPath(), newConfig)
 
-	mdiff, err := json.Marshal(deef)
+	mdiff, patch, err := diffConfigs(config, newConfig)
 	if err != nil {
-		return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("Failed to create response: %v", err))
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.diff", fmt.Sprintf("%v", err))
 	}
 
 	rsp := &diff.Response{
-		Diff:           proto.String(string(mdiff)),
+		Diff:           proto.String(mdiff),
 		Patch:          proto.String(patch),
 		ExistingConfig: proto.String(string(config)),
 	}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/HailoOSS/protobuf/proto"
 
@@ -11,22 +12,29 @@ import (
 	"github.com/HailoOSS/platform/server"
 )
 
-// Explain will compile config and then explain from which ID the "winning" piece of config came
+// Explain will compile config and then explain from which ID the "winning" piece of config came.
+// An asOf time explains the config as it was compiled at that point.
 func Explain(req *server.Request) (proto.Message, errors.Error) {
 	request := &explain.Request{}
 	if err := req.Unmarshal(request); err != nil {
 		return nil, errors.BadRequest("com.HailoOSS.service.config.explain", fmt.Sprintf("%v", err))
 	}
 
-	config, err := domain.ExplainConfig(request.GetId(), request.GetPath())
-	if err == domain.ErrPathNotFound {
+	config, configs, err := domain.ExplainConfigWithOptions(request.GetId(), request.GetPath(), &domain.CompileOptions{
+		AsOf: protoToTime(request.AsOf, time.Time{}),
+	})
+	if err == domain.ErrPathNotFound || err == domain.ErrRevisionNotFound {
 		return nil, errors.NotFound("com.HailoOSS.service.config.explain", fmt.Sprintf("%v", err))
 	}
+	if ierr, ok := err.(*domain.InterpolationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.explain.interpolation", ierr.Error())
+	}
 	if err != nil {
 		return nil, errors.InternalServerError("com.HailoOSS.service.config.explain", fmt.Sprintf("%v", err))
 	}
 
 	return &explain.Response{
 		Config: proto.String(string(config)),
+		Layer:  changesToLayers(configs),
 	}, nil
 }
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	list "github.com/HailoOSS/config-service/proto/list"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+const (
+	defaultListCount = 100
+	maxListCount     = 1000
+)
+
+// List returns a page of the IDs which exist, optionally only those with a given prefix
+func List(req *server.Request) (proto.Message, errors.Error) {
+	request := &list.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.list", fmt.Sprintf("%v", err))
+	}
+
+	count := int(request.GetCount())
+	if count <= 0 {
+		count = defaultListCount
+	}
+	if count > maxListCount {
+		count = maxListCount
+	}
+
+	css, cursor, err := domain.ListIds(request.GetPrefix(), request.GetCursor(), count)
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.list", fmt.Sprintf("%v", err))
+	}
+
+	ids := make([]*list.Response_Id, len(css))
+	for i, cs := range css {
+		ids[i] = &list.Response_Id{
+			Id:   proto.String(cs.Id),
+			Meta: changeToProto(cs),
+		}
+	}
+
+	return &list.Response{
+		Ids:    ids,
+		Cursor: proto.String(cursor),
+	}, nil
+}
@@ -1,14 +1,30 @@
 package handler
 
 import (
+	"encoding/json"
 	"strconv"
+	"strings"
 	"time"
 
+	log "github.com/cihub/seelog"
+
 	"github.com/HailoOSS/config-service/domain"
 	common "github.com/HailoOSS/config-service/proto"
+	batchupdate "github.com/HailoOSS/config-service/proto/batchupdate"
 	"github.com/HailoOSS/protobuf/proto"
 )
 
+// redactionPolicy returns the policy for redacting sensitive values from changes before
+// they leave the service, falling back to the default if it cannot be read
+func redactionPolicy() *domain.RedactionPolicy {
+	policy, err := domain.ReadRedactionPolicy()
+	if err != nil {
+		log.Warnf("Failed to read redaction policy, using the default: %v", err)
+		return domain.DefaultRedactionPolicy()
+	}
+	return policy
+}
+
 func changeToProto(c *domain.ChangeSet) *common.ChangeMeta {
 	return &common.ChangeMeta{
 		ChangeId:      proto.String(c.ChangeId),
@@ -16,27 +32,73 @@ func changeToProto(c *domain.ChangeSet) *common.ChangeMeta {
 		AuthMechanism: proto.String(c.UserMech),
 		UserId:        proto.String(c.UserId),
 		Message:       proto.String(c.Message),
+		Revision:      proto.Int64(c.Revision),
 	}
 }
 
-func changeToFullProto(c *domain.ChangeSet) *common.Change {
+func changeToFullProto(c *domain.ChangeSet, policy *domain.RedactionPolicy) *common.Change {
+	if c.Id == domain.SecretsId {
+		masked := *c
+		masked.Body = domain.MaskSecretsConfig(c.Id, c.Body)
+		masked.OldConfig = domain.MaskSecretsConfig(c.Id, c.OldConfig)
+		masked.Patch = domain.MaskSecretsConfig(c.Id, c.Patch)
+		masked.MergePatch = domain.MaskSecretsConfig(c.Id, c.MergePatch)
+		masked.NewConfig = domain.MaskSecretsConfig(c.Id, c.NewConfig)
+		c = &masked
+	}
+	c = policy.RedactChange(c)
+
+	var expiresAt *int64
+	if !c.ExpiresAt.IsZero() {
+		expiresAt = proto.Int64(c.ExpiresAt.Unix())
+	}
+	var rolledBackTo *int64
+	if c.RolledBack {
+		rolledBackTo = proto.Int64(c.RolledBackTo)
+	}
+
 	return &common.Change{
-		ChangeId:      proto.String(c.ChangeId),
-		Id:            proto.String(c.Id),
-		Timestamp:     proto.Int64(c.Timestamp.Unix()),
-		AuthMechanism: proto.String(c.UserMech),
-		UserId:        proto.String(c.UserId),
-		Message:       proto.String(c.Message),
-		Config:        proto.String(string(c.Body)),
-		Path:          proto.String(c.Path),
-		OldConfig:     proto.String(string(c.OldConfig)),
+		ChangeId:       proto.String(c.ChangeId),
+		Id:             proto.String(c.Id),
+		Timestamp:      proto.Int64(c.Timestamp.Unix()),
+		AuthMechanism:  proto.String(c.UserMech),
+		UserId:         proto.String(c.UserId),
+		Message:        proto.String(c.Message),
+		Config:         proto.String(string(c.Body)),
+		Path:           proto.String(c.Path),
+		OldConfig:      proto.String(string(c.OldConfig)),
+		SkipValidation: proto.Bool(c.SkipValidation),
+		Revision:       proto.Int64(c.Revision),
+		RolledBackTo:   rolledBackTo,
+		Patch:          proto.String(string(c.Patch)),
+		MergePatch:     proto.String(string(c.MergePatch)),
+		NewConfig:      proto.String(string(c.NewConfig)),
+		BatchId:        proto.String(c.BatchId),
+		Deleted:        proto.Bool(c.Deleted),
+		BreakGlass:     proto.Bool(c.BreakGlass),
+		ScheduleId:     proto.String(c.ScheduleId),
+		ExpiresAt:      expiresAt,
+		Reverts:        proto.String(c.Reverts),
 	}
 }
 
 func changesToFullProto(cs []*domain.ChangeSet) []*common.Change {
+	policy := redactionPolicy()
 	ret := make([]*common.Change, len(cs))
 	for i, c := range cs {
-		ret[i] = changeToFullProto(c)
+		ret[i] = changeToFullProto(c, policy)
+	}
+	return ret
+}
+
+func changesToLayers(cs []*domain.ChangeSet) []*common.Layer {
+	ret := make([]*common.Layer, len(cs))
+	for i, c := range cs {
+		ret[i] = &common.Layer{
+			Id:       proto.String(c.Id),
+			ChangeId: proto.String(c.ChangeId),
+			Revision: proto.Int64(c.Revision),
+		}
 	}
 	return ret
 }
@@ -48,9 +110,25 @@ func protoToTime(t *int64, def time.Time) time.Time {
 	return time.Unix(*t, 0)
 }
 
-func changeToNSQ(action, changeId, id, path, mech, user, message, config, previousConfig string) *NSQEvent {
+// patchActions are those events whose config is a JSON Patch, rather than config at a path
+var patchActions = map[string]bool{
+	"PATCHED":         true,
+	"SCHEDULED_PATCH": true,
+}
+
+func changeToNSQ(action, changeId, id, path, mech, user, message, config, previousConfig string, breakGlass bool) *NSQEvent {
+	config = string(domain.MaskSecretsConfig(id, []byte(config)))
+	previousConfig = string(domain.MaskSecretsConfig(id, []byte(previousConfig)))
+
+	policy := redactionPolicy()
+	if patchActions[action] {
+		config = string(policy.RedactPatch(id, []byte(config)))
+	} else {
+		config = string(policy.Redact(id, path, []byte(config)))
+	}
+	previousConfig = string(policy.Redact(id, path, []byte(previousConfig)))
 
-	return &NSQEvent{
+	event := &NSQEvent{
 		Id:        changeId,
 		Timestamp: strconv.Itoa(int(time.Now().Unix())),
 		Type:      "com.HailoOSS.service.config.event",
@@ -65,4 +143,131 @@ func changeToNSQ(action, changeId, id, path, mech, user, message, config, previo
 			"PreviousConfig": previousConfig,
 		},
 	}
+
+	// Only flag changes which broke glass, so other events are as they always were
+	if breakGlass {
+		event.Details["BreakGlass"] = "true"
+	}
+	return event
+}
+
+// batchToNSQ returns the platform event for a batch update, published as one event for the
+// whole batch, with the sensitive values of each update redacted
+func batchToNSQ(batchId string, ids []string, mech, user, message string, updates []*batchupdate.Request_Update, breakGlass bool) *NSQEvent {
+	policy := redactionPolicy()
+	published := make([]*batchupdate.Request_Update, len(updates))
+	for i, u := range updates {
+		redacted := *u
+		config := domain.MaskSecretsConfig(u.GetId(), []byte(u.GetConfig()))
+		redacted.Config = proto.String(string(policy.Redact(u.GetId(), u.GetPath(), config)))
+		published[i] = &redacted
+	}
+	config, err := json.Marshal(published)
+	if err != nil {
+		config = []byte{}
+	}
+	return changeToNSQ("BATCHUPDATED", batchId, strings.Join(ids, ","), "", mech, user, message, string(config), "", breakGlass)
+}
+
+// changeAction returns the action of the platform event published when a change was made
+func changeAction(c *domain.ChangeSet) string {
+	switch {
+	case c.Deleted:
+		return "DELETED_ID"
+	case c.Id == domain.SecretsId:
+		// Only ever written by setsecret
+		return "SECRET_SET"
+	case c.Reverts != "":
+		return "REVERTED"
+	case c.RolledBack && len(c.OldConfig) == 0:
+		return "UNDELETED_ID"
+	case c.RolledBack:
+		return "ROLLEDBACK"
+	case c.ProposalId != "":
+		return "APPROVED"
+	case len(c.Patch) > 0:
+		return "PATCHED"
+	}
+	if _, err := c.ConfigAtPath(); c.Path != "" && err == domain.ErrPathNotFound {
+		return "DELETED"
+	}
+	return "UPDATED"
+}
+
+// ChangeEvent returns the platform event for a change from the change log, as published
+// when it was made, with its config masked and redacted. Changes written by a batch update
+// are published together, with BatchEvent.
+func ChangeEvent(c *domain.ChangeSet) *NSQEvent {
+	if c.BatchId != "" {
+		return BatchEvent([]*domain.ChangeSet{c})
+	}
+
+	action := changeAction(c)
+	mech, user, message := c.UserMech, c.UserId, c.Message
+
+	var config []byte
+	previousConfig := c.OldConfig
+	switch action {
+	case "PATCHED":
+		config = c.Patch
+	case "DELETED", "DELETED_ID":
+	case "SECRET_SET":
+		// Published without the value
+		previousConfig = nil
+	case "APPROVED":
+		// Published as made by the reviewer
+		mech, user, message = c.ReviewerMech, c.ReviewerId, c.ReviewMessage
+		config, _ = c.ConfigAtPath()
+	case "REVERTED":
+		message = c.Reverts
+		config, _ = c.ConfigAtPath()
+	default:
+		// Merge updates are published with the merge patch sent, not the merged config
+		config = c.MergePatch
+		if len(config) == 0 {
+			config, _ = c.ConfigAtPath()
+		}
+	}
+
+	event := changeToNSQ(action, c.ChangeId, c.Id, c.Path, mech, user, message,
+		string(config), string(previousConfig), c.BreakGlass)
+	event.Timestamp = strconv.Itoa(int(c.Timestamp.Unix()))
+	return event
+}
+
+// BatchEvent returns the platform event for the changes written by a batch update, in
+// order, as published when they were made: one event for the whole batch
+func BatchEvent(css []*domain.ChangeSet) *NSQEvent {
+	var ids []string
+	seen := make(map[string]bool, len(css))
+	updates := make([]*batchupdate.Request_Update, len(css))
+	for i, c := range css {
+		if !seen[c.Id] {
+			seen[c.Id] = true
+			ids = append(ids, c.Id)
+		}
+		u := &batchupdate.Request_Update{Id: proto.String(c.Id)}
+		if c.Path != "" {
+			u.Path = proto.String(c.Path)
+		}
+		config := c.MergePatch
+		if len(config) > 0 {
+			u.Merge = proto.Bool(true)
+		} else {
+			config, _ = c.ConfigAtPath()
+		}
+		u.Config = proto.String(string(config))
+		if c.ExpectedHash != "" {
+			u.ExpectedHash = proto.String(c.ExpectedHash)
+		}
+		if c.ExpectedRevision != 0 {
+			u.ExpectedRevision = proto.Int64(c.ExpectedRevision)
+		}
+		updates[i] = u
+	}
+
+	last := css[len(css)-1]
+	event := batchToNSQ(last.BatchId, ids, last.UserMech, last.UserId, last.Message, updates, last.BreakGlass)
+	event.Timestamp = strconv.Itoa(int(last.Timestamp.Unix()))
+	return event
 }
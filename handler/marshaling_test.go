@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/HailoOSS/config-service/domain"
+	gozk "github.com/HailoOSS/go-zookeeper/zk"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	platformtesting "github.com/HailoOSS/platform/testing"
+	"github.com/HailoOSS/protobuf/proto"
+	"github.com/HailoOSS/service/auth"
+	"github.com/HailoOSS/service/nsq"
+	ssync "github.com/HailoOSS/service/sync"
+	zk "github.com/HailoOSS/service/zookeeper"
+
+	batchupdate "github.com/HailoOSS/config-service/proto/batchupdate"
+	patch "github.com/HailoOSS/config-service/proto/patch"
+	setsecret "github.com/HailoOSS/config-service/proto/setsecret"
+	uproto "github.com/HailoOSS/config-service/proto/update"
+)
+
+func TestChangeAction(t *testing.T) {
+	testCases := []struct {
+		change *domain.ChangeSet
+		action string
+	}{
+		{&domain.ChangeSet{Body: []byte(`{"foo":1}`)}, "UPDATED"},
+		{&domain.ChangeSet{Body: []byte(`{"foo":1}`), OldConfig: []byte(`{}`), RolledBack: true}, "ROLLEDBACK"},
+		{&domain.ChangeSet{Body: []byte(`{"foo":1}`), RolledBack: true}, "UNDELETED_ID"},
+		{&domain.ChangeSet{Id: domain.SecretsId, Body: []byte(`{"foo":"c2VjcmV0"}`), Path: "foo"}, "SECRET_SET"},
+	}
+	for i, tc := range testCases {
+		if action := changeAction(tc.change); action != tc.action {
+			t.Errorf("Expected %v for testcase %v, got %v", tc.action, i, action)
+		}
+	}
+}
+
+// recordingPublisher keeps the platform events published
+type recordingPublisher struct {
+	events []*NSQEvent
+}
+
+func (p *recordingPublisher) Publish(topic string, body []byte) error {
+	if topic != platformTopicName {
+		return nil
+	}
+	event := &NSQEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) MultiPublish(topic string, body [][]byte) error {
+	for _, b := range body {
+		if err := p.Publish(topic, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type EventsSuite struct {
+	platformtesting.Suite
+	zk            *zk.MockZookeeperClient
+	realPublisher nsq.Publisher
+	published     *recordingPublisher
+}
+
+func TestRunEventsSuite(t *testing.T) {
+	platformtesting.RunSuite(t, new(EventsSuite))
+}
+
+func (s *EventsSuite) SetupTest() {
+	s.Suite.SetupTest()
+
+	// Mock ZK
+	s.zk = &zk.MockZookeeperClient{}
+	zk.ActiveMockZookeeperClient = s.zk
+	zk.Connector = zk.MockConnector
+	ssync.SetRegionLockNamespace("com.HailoOSS.service.config")
+
+	// Record NSQ
+	s.realPublisher = nsq.DefaultPublisher
+	s.published = &recordingPublisher{}
+	nsq.DefaultPublisher = s.published
+}
+
+func (s *EventsSuite) TearDownTest() {
+	s.Suite.TearDownTest()
+	s.zk.On("Close").Return().Once()
+	zk.ActiveMockZookeeperClient = nil
+	zk.Connector = zk.DefaultConnector
+	zk.TearDown()
+	nsq.DefaultPublisher = s.realPublisher
+}
+
+func (s *EventsSuite) mockLock(id string) {
+	lock := &zk.MockLock{}
+	lock.On("Lock").Return(nil)
+	lock.On("Unlock").Return(nil)
+	lock.On("SetTTL", mock.AnythingOfType("time.Duration")).Return()
+	lock.On("SetTimeout", mock.AnythingOfType("time.Duration")).Return()
+
+	lockPath := fmt.Sprintf("/com.HailoOSS.service.config/%s", id)
+	s.zk.
+		On("NewLock", lockPath, gozk.WorldACL(gozk.PermAll)).
+		Return(lock)
+	s.zk.On("Exists", lockPath).Return(false, &gozk.Stat{}, nil)
+	s.zk.On("Delete", lockPath, int32(-1)).Return(nil)
+}
+
+// TestReplayedEvents checks that the events replayed from the change log, as sent on
+// /events, are those published when the changes were made
+func (s *EventsSuite) TestReplayedEvents() {
+	keyfile, err := ioutil.TempFile("", "secrets")
+	s.NoError(err)
+	defer os.Remove(keyfile.Name())
+	_, err = keyfile.WriteString(hex.EncodeToString([]byte("0123456789abcdef0123456789abcdef")) + "\n")
+	s.NoError(err)
+	keyfile.Close()
+	domain.DefaultKeyProvider = &domain.KeyfileProvider{Path: keyfile.Name()}
+	defer func() { domain.DefaultKeyProvider = nil }()
+
+	base, region := "H2:BASE", "H2:REGION:eu-west-1"
+	domain.DefaultRepository = domain.NewMemoryRepository(map[string]*domain.ChangeSet{})
+	for _, id := range []string{base, region, domain.SecretsId} {
+		s.mockLock(id)
+	}
+
+	since := time.Now()
+	scope := &auth.MockScope{MockUid: "mockUid", MockRoles: []string{"ADMIN"}}
+	requests := []struct {
+		handler func(*server.Request) (proto.Message, errors.Error)
+		request proto.Message
+	}{
+		{Update, &uproto.Request{Id: proto.String(base), Path: proto.String("foo"), Message: proto.String("Update"),
+			Config: proto.String(`{"bar":1}`)}},
+		{Update, &uproto.Request{Id: proto.String(base), Path: proto.String("foo"), Message: proto.String("Merge"),
+			Config: proto.String(`{"baz":2}`), Merge: proto.Bool(true)}},
+		{Update, &uproto.Request{Id: proto.String(base), Path: proto.String("quiet"), Message: proto.String("No reload"),
+			Config: proto.String(`1`), NoReload: proto.Bool(true)}},
+		{Patch, &patch.Request{Id: proto.String(base), Message: proto.String("Patch"),
+			Patch: proto.String(`[{"op":"add","path":"/foo/qux","value":3}]`)}},
+		{BatchUpdate, &batchupdate.Request{Message: proto.String("Batch"), Update: []*batchupdate.Request_Update{
+			{Id: proto.String(base), Path: proto.String("foo/bar"), Config: proto.String(`4`)},
+			{Id: proto.String(region), Path: proto.String("foo"), Config: proto.String(`{"bar":5}`), Merge: proto.Bool(true)},
+			{Id: proto.String(base), Path: proto.String("foo/baz"), Config: proto.String(`6`)},
+		}}},
+		{SetSecret, &setsecret.Request{Name: proto.String("monitoring/apiPassword"), Value: proto.String("hunter2"),
+			Message: proto.String("Password")}},
+	}
+	for i, r := range requests {
+		req := newTestRequest(r.request)
+		req.SetAuth(scope)
+		_, err := r.handler(req)
+		s.NoError(err, "Unexpected error for request %v", i)
+	}
+
+	css, err := domain.ChangesSince(since)
+	s.NoError(err)
+	var replayed []*NSQEvent
+	for i, cs := range css {
+		switch {
+		case cs.NoReload:
+		case cs.BatchId == "":
+			replayed = append(replayed, ChangeEvent(cs))
+		case i == len(css)-1 || css[i+1].BatchId != cs.BatchId:
+			var batch []*domain.ChangeSet
+			for _, bcs := range css {
+				if bcs.BatchId == cs.BatchId {
+					batch = append(batch, bcs)
+				}
+			}
+			replayed = append(replayed, BatchEvent(batch))
+		}
+	}
+
+	s.Len(replayed, len(s.published.events))
+	for i, event := range s.published.events {
+		if i >= len(replayed) {
+			break
+		}
+		// Published with the time sent, rather than the time of the change
+		event.Timestamp = replayed[i].Timestamp
+		s.Equal(event, replayed[i], "Replayed event %v differs from that published", i)
+	}
+}
@@ -20,7 +20,7 @@ func MultiCompile(req *server.Request) (proto.Message, errors.Error) {
 
 	compileResponses := make([]*multicompile.Response_CompileResponse, len(request.GetCompileRequests()))
 	for i, compileRequest := range request.GetCompileRequests() {
-		cfg, hash, err := DoCompile(compileRequest.GetId(), compileRequest.GetPath())
+		cfg, hash, _, err := DoCompile(compileRequest.GetId(), compileRequest.GetPath(), nil)
 		if err != nil {
 			compileResponses[i] = &multicompile.Response_CompileResponse{
 				Config: proto.String(""),
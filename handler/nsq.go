@@ -23,11 +23,14 @@ func broadcastChange(id string) {
 	}
 }
 
-func pubNSQEvent(action, changeId, id, path, mech, user, message, config, previousConfig string) {
-	event := changeToNSQ(action, changeId, id, path, mech, user, message, config, previousConfig)
+func pubNSQEvent(action, changeId, id, path, mech, user, message, config, previousConfig string, breakGlass bool) {
+	pubEvent(changeToNSQ(action, changeId, id, path, mech, user, message, config, previousConfig, breakGlass))
+}
+
+func pubEvent(event *NSQEvent) {
 	bytes, err := json.Marshal(event)
 	if err != nil {
-		log.Errorf("Error marshaling nsq event message for %v:%v", changeId, err)
+		log.Errorf("Error marshaling nsq event message for %v:%v", event.Id, err)
 		return
 	}
 	err = nsq.Publish(platformTopicName, bytes)
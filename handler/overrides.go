@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	overrides "github.com/HailoOSS/config-service/proto/overrides"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Overrides lists temporary overrides, soonest to expire first, along with whether they
+// have been reverted
+func Overrides(req *server.Request) (proto.Message, errors.Error) {
+	request := &overrides.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.overrides", fmt.Sprintf("%v", err))
+	}
+
+	all, err := domain.ListOverrides(request.GetId(), request.GetActive())
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.overrides", fmt.Sprintf("%v", err))
+	}
+
+	policy := redactionPolicy()
+	rsp := &overrides.Response{
+		Overrides: make([]*overrides.Response_Override, len(all)),
+	}
+	for i, o := range all {
+		config := policy.Redact(o.Id, o.Path, domain.MaskSecretsConfig(o.Id, o.Config))
+		oldConfig := policy.Redact(o.Id, o.Path, domain.MaskSecretsConfig(o.Id, o.OldConfig))
+		rsp.Overrides[i] = &overrides.Response_Override{
+			OverrideId:     proto.String(o.OverrideId),
+			Id:             proto.String(o.Id),
+			Path:           proto.String(o.Path),
+			Config:         proto.String(string(config)),
+			OldConfig:      proto.String(string(oldConfig)),
+			ExpiresAt:      proto.Int64(o.ExpiresAt.Unix()),
+			AuthMechanism:  proto.String(o.UserMech),
+			UserId:         proto.String(o.UserId),
+			Message:        proto.String(o.Message),
+			Timestamp:      proto.Int64(o.Timestamp.Unix()),
+			Status:         proto.String(string(o.Status)),
+			RevertChangeId: proto.String(o.RevertChangeId),
+			Error:          proto.String(o.Error),
+		}
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/config-service/domain"
+	patch "github.com/HailoOSS/config-service/proto/patch"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// Patch applies an RFC 6902 JSON Patch to the config for the given ID, as a single change
+func Patch(req *server.Request) (proto.Message, errors.Error) {
+	request := &patch.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.patch", fmt.Sprintf("%v", err))
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.patch.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	paths, err := domain.PatchPaths([]byte(request.GetPatch()))
+	if err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.patch", fmt.Sprintf("%v", err))
+	}
+	if err := checkAccess(req, "patch", u4.String(), request.GetId(), paths, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("patch", request.GetId()); err != nil {
+		return nil, err
+	}
+
+	previousConfig, _, err := domain.ReadConfig(request.GetId(), "")
+	if err != nil {
+		log.Warnf("Unable to read previous config on patch: %s", err.Error())
+	}
+
+	err = domain.PatchConfig(
+		u4.String(),
+		request.GetId(),
+		mech,
+		id,
+		request.GetMessage(),
+		[]byte(request.GetPatch()),
+		&domain.WriteOptions{
+			SkipValidation:   request.GetSkipValidation(),
+			ValidateCompiled: request.GetValidateCompiled(),
+			ExpectedHash:     request.GetExpectedHash(),
+			ExpectedRevision: request.GetExpectedRevision(),
+			BreakGlass:       request.GetBreakGlass(),
+			NoReload:         request.GetNoReload(),
+		},
+	)
+	if perr, ok := err.(*domain.PatchError); ok {
+		if perr.TestFailed {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.patch.testfailed", perr.Error())
+		}
+		return nil, errors.BadRequest("com.HailoOSS.service.config.patch", perr.Error())
+	}
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.patch.invalid", verr.Error())
+	}
+	if err == domain.ErrConfigChanged {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.patch.stale", fmt.Sprintf("%v", err))
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.patch.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.patch.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.patch", fmt.Sprintf("%v", err))
+	}
+
+	if !request.GetNoReload() {
+		broadcastChange(request.GetId())
+
+		// Pub the change to the platform event stream
+		pubNSQEvent("PATCHED", u4.String(), request.GetId(), "", mech, id, request.GetMessage(), request.GetPatch(), string(previousConfig), request.GetBreakGlass())
+	}
+
+	return &patch.Response{}, nil
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	proposals "github.com/HailoOSS/config-service/proto/proposals"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Proposals lists proposed changes, along with their diffs and reviews
+func Proposals(req *server.Request) (proto.Message, errors.Error) {
+	request := &proposals.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.proposals", fmt.Sprintf("%v", err))
+	}
+
+	var ps []*domain.Proposal
+	if proposalId := request.GetProposalId(); proposalId != "" {
+		p, err := domain.ReadProposal(proposalId)
+		if err == domain.ErrProposalNotFound {
+			return nil, errors.NotFound("com.HailoOSS.service.config.proposals", fmt.Sprintf("%v", err))
+		}
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.proposals", fmt.Sprintf("%v", err))
+		}
+		ps = []*domain.Proposal{p}
+	} else {
+		var err error
+		ps, err = domain.ListProposals(request.GetId(), request.GetPending())
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.proposals", fmt.Sprintf("%v", err))
+		}
+	}
+
+	policy := redactionPolicy()
+	rsp := &proposals.Response{
+		Proposals: make([]*proposals.Response_Proposal, len(ps)),
+	}
+	for i, p := range ps {
+		mdiff, patch, err := proposalDiff(p, policy)
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.proposals", fmt.Sprintf("%v", err))
+		}
+		rsp.Proposals[i] = &proposals.Response_Proposal{
+			ProposalId: proto.String(p.ProposalId),
+			Status:     proto.String(string(p.Status)),
+			Change:     changeToFullProto(p.Change, policy),
+			Diff:       proto.String(mdiff),
+			Patch:      proto.String(patch),
+		}
+		if p.Status == domain.ProposalApproved || p.Status == domain.ProposalRejected {
+			rsp.Proposals[i].ReviewAuthMechanism = proto.String(p.ReviewerMech)
+			rsp.Proposals[i].ReviewUserId = proto.String(p.ReviewerId)
+			rsp.Proposals[i].ReviewMessage = proto.String(p.ReviewMessage)
+			rsp.Proposals[i].ReviewTimestamp = proto.Int64(p.ReviewedAt.Unix())
+		}
+	}
+
+	return rsp, nil
+}
+
+// proposalDiff returns the diff of the config at the path of a proposal before and after
+// the change, with secrets masked and sensitive values redacted
+func proposalDiff(p *domain.Proposal, policy *domain.RedactionPolicy) (string, string, error) {
+	config, err := p.ChangedConfig()
+	if err != nil {
+		return "", "", err
+	}
+	cs := p.Change
+	oldConfig := policy.Redact(cs.Id, cs.Path, domain.MaskSecretsConfig(cs.Id, cs.OldConfig))
+	config = policy.Redact(cs.Id, cs.Path, domain.MaskSecretsConfig(cs.Id, config))
+	return diffConfigs(oldConfig, config)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	propose "github.com/HailoOSS/config-service/proto/propose"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// Propose stores a change to the config for the given ID, as update would make it, to be
+// applied only once approved by someone else
+func Propose(req *server.Request) (proto.Message, errors.Error) {
+	request := &propose.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.propose", fmt.Sprintf("%v", err))
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.propose.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	if err := checkAccess(req, "propose", u4.String(), request.GetId(), []string{request.GetPath()}, mech, id); err != nil {
+		return nil, err
+	}
+
+	p, err := domain.Propose(
+		u4.String(),
+		request.GetId(),
+		request.GetPath(),
+		mech,
+		id,
+		request.GetMessage(),
+		[]byte(request.GetConfig()),
+		&domain.WriteOptions{
+			SkipValidation:   request.GetSkipValidation(),
+			ValidateCompiled: request.GetValidateCompiled(),
+			ExpectedHash:     request.GetExpectedHash(),
+			ExpectedRevision: request.GetExpectedRevision(),
+			Merge:            request.GetMerge(),
+		},
+	)
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.propose.invalid", verr.Error())
+	}
+	if err == domain.ErrConfigChanged {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.propose.stale", fmt.Sprintf("%v", err))
+	}
+	if cerr, ok := err.(*domain.ErrPathConflict); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.propose.conflict", cerr.Error())
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.propose", fmt.Sprintf("%v", err))
+	}
+
+	mdiff, patch, err := proposalDiff(p, redactionPolicy())
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.propose", fmt.Sprintf("%v", err))
+	}
+
+	// Pub the proposal to the platform event stream
+	pubNSQEvent("PROPOSED", u4.String(), request.GetId(), request.GetPath(), mech, id, request.GetMessage(),
+		request.GetConfig(), string(p.Change.OldConfig), false)
+
+	return &propose.Response{
+		ProposalId: proto.String(p.ProposalId),
+		Diff:       proto.String(mdiff),
+		Patch:      proto.String(patch),
+	}, nil
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/HailoOSS/protobuf/proto"
 
 	"github.com/HailoOSS/config-service/domain"
@@ -10,14 +12,25 @@ import (
 )
 
 // Read will read a single ID config - and should only be used when editing config (use compile when reading for use)
+// A revision or timestamp may be given to read the config as it was at that point
 func Read(req *server.Request) (proto.Message, errors.Error) {
 	request := &read.Request{}
 	if err := req.Unmarshal(request); err != nil {
 		return nil, errors.BadRequest(server.Name+".read", err.Error())
 	}
 
-	config, change, err := domain.ReadConfig(request.GetId(), request.GetPath())
-	if err == domain.ErrPathNotFound || err == domain.ErrIdNotFound {
+	var config []byte
+	var change *domain.ChangeSet
+	var err error
+	switch {
+	case request.Revision != nil:
+		config, change, err = domain.ReadConfigAtRevision(request.GetId(), request.GetPath(), request.GetRevision())
+	case request.Timestamp != nil:
+		config, change, err = domain.ReadConfigAt(request.GetId(), request.GetPath(), time.Unix(request.GetTimestamp(), 0))
+	default:
+		config, change, err = domain.ReadConfig(request.GetId(), request.GetPath())
+	}
+	if err == domain.ErrPathNotFound || err == domain.ErrIdNotFound || err == domain.ErrRevisionNotFound {
 		return nil, errors.NotFound(server.Name+".read.notfound", err.Error())
 	}
 	if err != nil {
@@ -25,7 +38,7 @@ func Read(req *server.Request) (proto.Message, errors.Error) {
 	}
 
 	return &read.Response{
-		Config: proto.String(string(config)),
+		Config: proto.String(string(domain.MaskSecretsConfig(request.GetId(), config))),
 		Hash:   proto.String(createConfigHash(config)),
 		Meta:   changeToProto(change),
 	}, nil
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"time"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/config-service/domain"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// RunReaper reverts temporary overrides as they expire, checking every interval. Every
+// instance runs it, and whichever gets the reaper lock reverts them.
+func RunReaper(interval time.Duration) {
+	for range time.Tick(interval) {
+		revertExpiredOverrides()
+	}
+}
+
+func newChangeId() (string, error) {
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	return u4.String(), nil
+}
+
+// revertExpiredOverrides reverts any overrides now expired, as this service, broadcasting
+// and publishing each revert as the change endpoints would
+func revertExpiredOverrides() {
+	overrides, err := domain.RevertExpiredOverrides(time.Now(), defaultMech, server.Name, newChangeId)
+	if err != nil {
+		log.Warnf("Failed to revert expired overrides: %v", err)
+	}
+
+	for _, o := range overrides {
+		switch o.Status {
+		case domain.OverrideReverted:
+			broadcastChange(o.Id)
+
+			// Pub the revert to the platform event stream
+			pubNSQEvent("REVERTED", o.RevertChangeId, o.Id, o.Path, defaultMech, server.Name, o.OverrideId,
+				string(o.OldConfig), string(o.Config), false)
+		case domain.OverrideSuperseded:
+			log.Infof("Left expired override %s of %s at %q, as it has changed since", o.OverrideId, o.Id, o.Path)
+		default:
+			log.Warnf("Failed to revert expired override %s of %s at %q: %s", o.OverrideId, o.Id, o.Path, o.Error)
+			pubNSQEvent("REVERT_FAILED", o.OverrideId, o.Id, o.Path, defaultMech, server.Name, o.Error, "", "", false)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	reject "github.com/HailoOSS/config-service/proto/reject"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Reject closes a pending proposal without applying it, and must be done by someone
+// other than the proposer
+func Reject(req *server.Request) (proto.Message, errors.Error) {
+	request := &reject.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.reject", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	p, err := domain.RejectProposal(request.GetProposalId(), mech, id, request.GetMessage())
+	if err == domain.ErrProposalNotFound {
+		return nil, errors.NotFound("com.HailoOSS.service.config.reject", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrProposalClosed {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.reject.closed", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrSelfReview {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.reject.self", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.reject", fmt.Sprintf("%v", err))
+	}
+
+	// Pub the rejection to the platform event stream
+	pubNSQEvent("REJECTED", p.ProposalId, p.Change.Id, p.Change.Path, mech, id, request.GetMessage(), "", "", false)
+
+	return &reject.Response{}, nil
+}
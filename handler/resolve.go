@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	common "github.com/HailoOSS/config-service/proto"
+	resolve "github.com/HailoOSS/config-service/proto/resolve"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Resolve builds the chain of IDs for a service from the hierarchy stored in the config
+// service, and compiles them, so that clients need not know the hierarchy
+func Resolve(req *server.Request) (proto.Message, errors.Error) {
+	request := &resolve.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.resolve", fmt.Sprintf("%v", err))
+	}
+	if request.GetRevealSecrets() && !req.Auth().HasAccess(secretsRole) {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.resolve.secrets", "Not authorised to reveal secrets")
+	}
+
+	vars := map[string]string{
+		"service": request.GetService(),
+		"region":  request.GetRegion(),
+		"env":     request.GetEnv(),
+		"az":      request.GetAz(),
+	}
+	for _, v := range request.GetVariable() {
+		vars[v.GetName()] = v.GetValue()
+	}
+
+	ids, cfg, hash, layers, err := DoResolve(vars, request.GetPath(), &domain.CompileOptions{
+		Strict:        request.GetStrict(),
+		RevealSecrets: request.GetRevealSecrets(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resolve.Response{
+		Config: proto.String(cfg),
+		Hash:   proto.String(hash),
+		Id:     ids,
+		Layer:  layers,
+	}, nil
+}
+
+// DoResolve does the real work for resolve, for both the platform and HTTP interfaces.
+// The variables also fill in any ${var:name} references within the compiled config.
+func DoResolve(vars map[string]string, path string, opts *domain.CompileOptions) (ids []string, config, hash string, layers []*common.Layer, resolveErr errors.Error) {
+	opts.Vars = make(map[string]string, len(vars))
+	for name, value := range vars {
+		if value != "" {
+			opts.Vars[name] = value
+		}
+	}
+
+	ids, err := domain.ResolveIds(opts.Vars)
+	if err != nil {
+		resolveErr = errors.InternalServerError("com.HailoOSS.service.config.resolve", fmt.Sprintf("%v", err))
+		return
+	}
+	if len(ids) == 0 {
+		resolveErr = errors.BadRequest("com.HailoOSS.service.config.resolve", "No layers of the hierarchy could be resolved")
+		return
+	}
+
+	config, hash, layers, resolveErr = DoCompile(ids, path, opts)
+	return
+}
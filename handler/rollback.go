@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	rollback "github.com/HailoOSS/config-service/proto/rollback"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// Rollback restores the whole config for an ID to a previous revision, identified by revision,
+// change ID or the number of changes to undo, writing it as a new change
+func Rollback(req *server.Request) (proto.Message, errors.Error) {
+	request := &rollback.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.rollback", fmt.Sprintf("%v", err))
+	}
+	// A message is generated if none is given, but breaking glass needs a real reason
+	if request.GetBreakGlass() && request.GetMessage() == "" {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.rollback.breakglass", domain.ErrBreakGlassMessage.Error())
+	}
+
+	targets := 0
+	for _, set := range []bool{request.Revision != nil, request.ChangeId != nil, request.Undo != nil} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.rollback", "Exactly one of revision, changeId or undo must be given")
+	}
+	if request.Undo != nil && request.GetUndo() <= 0 {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.rollback", "Undo must be at least 1")
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.rollback.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	if err := checkAccess(req, "rollback", u4.String(), request.GetId(), []string{""}, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("rollback", request.GetId()); err != nil {
+		return nil, err
+	}
+
+	cs, err := domain.RollbackConfig(
+		u4.String(),
+		request.GetId(),
+		mech,
+		id,
+		request.GetMessage(),
+		&domain.RollbackTarget{
+			Revision: request.GetRevision(),
+			ChangeId: request.GetChangeId(),
+			Undo:     request.GetUndo(),
+		},
+		&domain.WriteOptions{
+			SkipValidation: request.GetSkipValidation(),
+			BreakGlass:     request.GetBreakGlass(),
+		},
+	)
+	if err == domain.ErrIdNotFound || err == domain.ErrRevisionNotFound {
+		return nil, errors.NotFound("com.HailoOSS.service.config.rollback", fmt.Sprintf("%v", err))
+	}
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.rollback.invalid", verr.Error())
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.rollback.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.rollback.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.rollback", fmt.Sprintf("%v", err))
+	}
+
+	broadcastChange(request.GetId())
+
+	// Pub the change to the platform event stream
+	pubNSQEvent("ROLLEDBACK", u4.String(), request.GetId(), "", mech, id, cs.Message, string(cs.Body), string(cs.OldConfig), cs.BreakGlass)
+
+	return &rollback.Response{
+		Revision:     proto.Int64(cs.Revision),
+		RolledBackTo: proto.Int64(cs.RolledBackTo),
+	}, nil
+}
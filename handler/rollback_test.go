@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	rollback "github.com/HailoOSS/config-service/proto/rollback"
+)
+
+func TestRollbackRejectsNonPositiveUndo(t *testing.T) {
+	for _, undo := range []int64{0, -1} {
+		_, err := Rollback(newTestRequest(&rollback.Request{
+			Id:   proto.String("H2:BASE"),
+			Undo: proto.Int64(undo),
+		}))
+		if err == nil {
+			t.Fatalf("Expected undo %v to be rejected", undo)
+		}
+		if err.Code() != "com.HailoOSS.service.config.rollback" || err.HttpCode() != 400 {
+			t.Errorf("Expected a bad request for undo %v, got %v %v", undo, err.Code(), err.HttpCode())
+		}
+	}
+}
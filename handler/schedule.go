@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	schedule "github.com/HailoOSS/config-service/proto/schedule"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// Schedule stores an update, or a patch, to the config for the given ID, to be made by
+// the scheduler at the given time
+func Schedule(req *server.Request) (proto.Message, errors.Error) {
+	request := &schedule.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.schedule", fmt.Sprintf("%v", err))
+	}
+	if (request.Config == nil) == (request.Patch == nil) {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.schedule", "Exactly one of config or patch should be given")
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.schedule.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	paths := []string{request.GetPath()}
+	if request.Patch != nil {
+		if paths, err = domain.PatchPaths([]byte(request.GetPatch())); err != nil {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.schedule", fmt.Sprintf("%v", err))
+		}
+	}
+	if err := checkAccess(req, "schedule", u4.String(), request.GetId(), paths, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("schedule", request.GetId()); err != nil {
+		return nil, err
+	}
+
+	sc, err := domain.ScheduleChange(
+		u4.String(),
+		request.GetId(),
+		request.GetPath(),
+		mech,
+		id,
+		request.GetMessage(),
+		[]byte(request.GetConfig()),
+		[]byte(request.GetPatch()),
+		time.Unix(request.GetApplyAt(), 0),
+		&domain.WriteOptions{
+			SkipValidation:   request.GetSkipValidation(),
+			ValidateCompiled: request.GetValidateCompiled(),
+			Merge:            request.GetMerge(),
+			BreakGlass:       request.GetBreakGlass(),
+		},
+	)
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.schedule.invalid", verr.Error())
+	}
+	if err == domain.ErrScheduleInPast {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.schedule.past", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.schedule.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.schedule", fmt.Sprintf("%v", err))
+	}
+
+	// Pub the scheduled change to the platform event stream
+	if sc.IsPatch() {
+		pubNSQEvent("SCHEDULED_PATCH", sc.ScheduleId, sc.Id, "", mech, id, sc.Message, string(sc.Patch), "", sc.BreakGlass)
+	} else {
+		pubNSQEvent("SCHEDULED", sc.ScheduleId, sc.Id, sc.Path, mech, id, sc.Message, string(sc.Config), "", sc.BreakGlass)
+	}
+
+	return &schedule.Response{
+		ScheduleId: proto.String(sc.ScheduleId),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/config-service/domain"
+)
+
+// RunScheduler applies scheduled changes as they fall due, checking every interval.
+// Every instance runs it, and whichever gets the scheduler lock applies them.
+func RunScheduler(interval time.Duration) {
+	for range time.Tick(interval) {
+		applyDueChanges()
+	}
+}
+
+// applyDueChanges makes any scheduled changes now due, broadcasting and publishing each
+// as the change endpoints would
+func applyDueChanges() {
+	scs, err := domain.ApplyDueChanges(time.Now())
+	if err != nil {
+		log.Warnf("Failed to apply scheduled changes: %v", err)
+	}
+
+	for _, sc := range scs {
+		if sc.Status != domain.ScheduleApplied {
+			log.Warnf("Failed to apply scheduled change %s to %s: %s", sc.ScheduleId, sc.Id, sc.Error)
+			pubNSQEvent("SCHEDULE_FAILED", sc.ScheduleId, sc.Id, sc.Path, sc.UserMech, sc.UserId,
+				fmt.Sprintf("%s: %s", sc.Message, sc.Error), "", "", sc.BreakGlass)
+			continue
+		}
+
+		broadcastChange(sc.Id)
+
+		// Pub the change to the platform event stream, as it would be if made directly
+		if sc.IsPatch() {
+			pubNSQEvent("PATCHED", sc.ChangeId, sc.Id, "", sc.UserMech, sc.UserId, sc.Message,
+				string(sc.Patch), string(sc.PreviousConfig), sc.BreakGlass)
+		} else {
+			pubNSQEvent("UPDATED", sc.ChangeId, sc.Id, sc.Path, sc.UserMech, sc.UserId, sc.Message,
+				string(sc.Config), string(sc.PreviousConfig), sc.BreakGlass)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	schedules "github.com/HailoOSS/config-service/proto/schedules"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Schedules lists scheduled changes, soonest first, along with whether they were applied
+func Schedules(req *server.Request) (proto.Message, errors.Error) {
+	request := &schedules.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.schedules", fmt.Sprintf("%v", err))
+	}
+
+	var scs []*domain.ScheduledChange
+	if scheduleId := request.GetScheduleId(); scheduleId != "" {
+		sc, err := domain.ReadScheduledChange(scheduleId)
+		if err == domain.ErrScheduleNotFound {
+			return nil, errors.NotFound("com.HailoOSS.service.config.schedules", fmt.Sprintf("%v", err))
+		}
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.schedules", fmt.Sprintf("%v", err))
+		}
+		scs = []*domain.ScheduledChange{sc}
+	} else {
+		var err error
+		scs, err = domain.ListScheduledChanges(request.GetId(), request.GetPending())
+		if err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.schedules", fmt.Sprintf("%v", err))
+		}
+	}
+
+	policy := redactionPolicy()
+	rsp := &schedules.Response{
+		Schedules: make([]*schedules.Response_Schedule, len(scs)),
+	}
+	for i, sc := range scs {
+		rsp.Schedules[i] = &schedules.Response_Schedule{
+			ScheduleId:       proto.String(sc.ScheduleId),
+			Id:               proto.String(sc.Id),
+			Path:             proto.String(sc.Path),
+			Merge:            proto.Bool(sc.Merge),
+			ApplyAt:          proto.Int64(sc.ApplyAt.Unix()),
+			AuthMechanism:    proto.String(sc.UserMech),
+			UserId:           proto.String(sc.UserId),
+			Message:          proto.String(sc.Message),
+			Timestamp:        proto.Int64(sc.Timestamp.Unix()),
+			SkipValidation:   proto.Bool(sc.SkipValidation),
+			ValidateCompiled: proto.Bool(sc.ValidateCompiled),
+			BreakGlass:       proto.Bool(sc.BreakGlass),
+			Status:           proto.String(string(sc.Status)),
+			ChangeId:         proto.String(sc.ChangeId),
+			Error:            proto.String(sc.Error),
+		}
+		if sc.IsPatch() {
+			patch := domain.MaskSecretsConfig(sc.Id, sc.Patch)
+			rsp.Schedules[i].Patch = proto.String(string(policy.RedactPatch(sc.Id, patch)))
+		} else {
+			config := domain.MaskSecretsConfig(sc.Id, sc.Config)
+			rsp.Schedules[i].Config = proto.String(string(policy.Redact(sc.Id, sc.Path, config)))
+		}
+		if sc.Status == domain.ScheduleCancelled {
+			rsp.Schedules[i].CancelAuthMechanism = proto.String(sc.CancelledMech)
+			rsp.Schedules[i].CancelUserId = proto.String(sc.CancelledId)
+			rsp.Schedules[i].CancelMessage = proto.String(sc.CancelledMessage)
+			rsp.Schedules[i].CancelTimestamp = proto.Int64(sc.CancelledAt.Unix())
+		}
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	setsecret "github.com/HailoOSS/config-service/proto/setsecret"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// SetSecret encrypts and stores a secret, for config to refer to by name
+func SetSecret(req *server.Request) (proto.Message, errors.Error) {
+	request := &setsecret.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.setsecret", fmt.Sprintf("%v", err))
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.setsecret.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	if err := checkAccess(req, "setsecret", u4.String(), domain.SecretsId, []string{request.GetName()}, mech, id); err != nil {
+		return nil, err
+	}
+
+	err = domain.SetSecret(u4.String(), request.GetName(), mech, id, request.GetMessage(), request.GetValue())
+	if err == domain.ErrNoKeyProvider {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.setsecret.nokey", fmt.Sprintf("%v", err))
+	}
+	if cerr, ok := err.(*domain.ErrPathConflict); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.setsecret.conflict", cerr.Error())
+	}
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.setsecret.invalid", verr.Error())
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.setsecret.frozen", ferr.Error())
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.setsecret", fmt.Sprintf("%v", err))
+	}
+
+	broadcastChange(domain.SecretsId)
+
+	// Pub the change to the platform event stream, without the value
+	pubNSQEvent("SECRET_SET", u4.String(), domain.SecretsId, request.GetName(), mech, id, request.GetMessage(), "", "", false)
+
+	return &setsecret.Response{}, nil
+}
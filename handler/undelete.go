@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	undelete "github.com/HailoOSS/config-service/proto/undelete"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+	gouuid "github.com/nu7hatch/gouuid"
+)
+
+// Undelete restores a config ID removed by DeleteId, as it was when it was deleted
+func Undelete(req *server.Request) (proto.Message, errors.Error) {
+	request := &undelete.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.undelete", fmt.Sprintf("%v", err))
+	}
+	// A message is generated if none is given, but breaking glass needs a real reason
+	if request.GetBreakGlass() && request.GetMessage() == "" {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.undelete.breakglass", domain.ErrBreakGlassMessage.Error())
+	}
+
+	u4, err := gouuid.NewV4()
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.undelete.genid", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	if err := checkAccess(req, "undelete", u4.String(), request.GetId(), []string{""}, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("undelete", request.GetId()); err != nil {
+		return nil, err
+	}
+
+	cs, err := domain.UndeleteId(
+		u4.String(),
+		request.GetId(),
+		mech,
+		id,
+		request.GetMessage(),
+		&domain.WriteOptions{
+			SkipValidation: request.GetSkipValidation(),
+			BreakGlass:     request.GetBreakGlass(),
+		},
+	)
+	if err == domain.ErrIdNotFound {
+		return nil, errors.NotFound("com.HailoOSS.service.config.undelete", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrIdExists {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.undelete.exists", fmt.Sprintf("%v", err))
+	}
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.undelete.invalid", verr.Error())
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.undelete.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.undelete.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.undelete", fmt.Sprintf("%v", err))
+	}
+
+	broadcastChange(request.GetId())
+
+	// Pub the change to the platform event stream
+	pubNSQEvent("UNDELETED_ID", u4.String(), request.GetId(), "", mech, id, cs.Message, string(cs.Body), "", cs.BreakGlass)
+
+	return &undelete.Response{
+		Revision: proto.Int64(cs.Revision),
+		Restored: proto.Int64(cs.RolledBackTo),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	unschedule "github.com/HailoOSS/config-service/proto/unschedule"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Unschedule cancels a scheduled change which has yet to be applied
+func Unschedule(req *server.Request) (proto.Message, errors.Error) {
+	request := &unschedule.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.unschedule", fmt.Sprintf("%v", err))
+	}
+
+	var mech, id string
+	if user := req.Auth().AuthUser(); user != nil {
+		mech = user.Mech
+		id = user.Id
+	} else {
+		mech = defaultMech
+		id = req.From()
+	}
+
+	sc, err := domain.ReadScheduledChange(request.GetScheduleId())
+	if err == domain.ErrScheduleNotFound {
+		return nil, errors.NotFound("com.HailoOSS.service.config.unschedule", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.unschedule", fmt.Sprintf("%v", err))
+	}
+
+	// Only those who could make the change may stop it being made
+	paths := []string{sc.Path}
+	if sc.IsPatch() {
+		if paths, err = domain.PatchPaths(sc.Patch); err != nil {
+			return nil, errors.InternalServerError("com.HailoOSS.service.config.unschedule", fmt.Sprintf("%v", err))
+		}
+	}
+	if err := checkAccess(req, "unschedule", sc.ScheduleId, sc.Id, paths, mech, id); err != nil {
+		return nil, err
+	}
+
+	sc, err = domain.CancelScheduledChange(sc.ScheduleId, mech, id, request.GetMessage())
+	if err == domain.ErrScheduleClosed {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.unschedule.closed", fmt.Sprintf("%v", err))
+	}
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.unschedule", fmt.Sprintf("%v", err))
+	}
+
+	// Pub the cancellation to the platform event stream
+	pubNSQEvent("UNSCHEDULED", sc.ScheduleId, sc.Id, sc.Path, mech, id, request.GetMessage(), "", "", false)
+
+	return &unschedule.Response{}, nil
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"time"
 
 	log "github.com/cihub/seelog"
 	"github.com/HailoOSS/protobuf/proto"
@@ -17,7 +18,8 @@ const (
 	defaultMech = "s2s"
 )
 
-// Update will completely replace configuration at this level in the path with the supplied config (for the given ID)
+// Update will completely replace configuration at this level in the path with the supplied config (for the given ID),
+// or in merge mode, deep merge the supplied config into it
 func Update(req *server.Request) (proto.Message, errors.Error) {
 	request := &update.Request{}
 	if err := req.Unmarshal(request); err != nil {
@@ -38,6 +40,21 @@ func Update(req *server.Request) (proto.Message, errors.Error) {
 		id = req.From()
 	}
 
+	var expiresAt time.Time
+	if request.Ttl != nil {
+		if request.GetTtl() <= 0 {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.update.ttl", "TTL should be a positive number of seconds")
+		}
+		expiresAt = time.Now().Add(time.Duration(request.GetTtl()) * time.Second)
+	}
+
+	if err := checkAccess(req, "update", u4.String(), request.GetId(), []string{request.GetPath()}, mech, id); err != nil {
+		return nil, err
+	}
+	if err := checkApproval("update", request.GetId()); err != nil {
+		return nil, err
+	}
+
 	previousConfig, _, err := domain.ReadConfig(request.GetId(), request.GetPath())
 	if err != nil {
 		log.Warnf("Unable to read previous config on update: %s", err.Error())
@@ -51,7 +68,35 @@ func Update(req *server.Request) (proto.Message, errors.Error) {
 		id,
 		request.GetMessage(),
 		[]byte(request.GetConfig()),
+		&domain.WriteOptions{
+			SkipValidation:   request.GetSkipValidation(),
+			ValidateCompiled: request.GetValidateCompiled(),
+			ExpectedHash:     request.GetExpectedHash(),
+			ExpectedRevision: request.GetExpectedRevision(),
+			Merge:            request.GetMerge(),
+			BreakGlass:       request.GetBreakGlass(),
+			NoReload:         request.GetNoReload(),
+			ExpiresAt:        expiresAt,
+		},
 	)
+	if verr, ok := err.(*domain.ValidationError); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.update.invalid", verr.Error())
+	}
+	if err == domain.ErrConfigChanged {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.update.stale", fmt.Sprintf("%v", err))
+	}
+	if cerr, ok := err.(*domain.ErrPathConflict); ok {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.update.conflict", cerr.Error())
+	}
+	if ferr, ok := err.(*domain.FrozenError); ok {
+		return nil, errors.Forbidden("com.HailoOSS.service.config.update.frozen", ferr.Error())
+	}
+	if err == domain.ErrBreakGlassMessage {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.update.breakglass", fmt.Sprintf("%v", err))
+	}
+	if err == domain.ErrExpiryInPast {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.update.ttl", fmt.Sprintf("%v", err))
+	}
 	if err != nil {
 		return nil, errors.InternalServerError("com.HailoOSS.service.config.update", fmt.Sprintf("%v", err))
 	}
@@ -60,7 +105,7 @@ func Update(req *server.Request) (proto.Message, errors.Error) {
 		broadcastChange(request.GetId())
 
 		// Pub the change to the platform event stream
-		pubNSQEvent("UPDATED", u4.String(), request.GetId(), request.GetPath(), mech, id, request.GetMessage(), request.GetConfig(), string(previousConfig))
+		pubNSQEvent("UPDATED", u4.String(), request.GetId(), request.GetPath(), mech, id, request.GetMessage(), request.GetConfig(), string(previousConfig), request.GetBreakGlass())
 	}
 
 	return &update.Response{}, nil
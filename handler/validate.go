@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/HailoOSS/protobuf/proto"
+
+	"github.com/HailoOSS/config-service/domain"
+	validate "github.com/HailoOSS/config-service/proto/validate"
+	"github.com/HailoOSS/platform/errors"
+	"github.com/HailoOSS/platform/server"
+)
+
+// Validate will compile config and then check it against every registered schema at or beneath the path
+func Validate(req *server.Request) (proto.Message, errors.Error) {
+	request := &validate.Request{}
+	if err := req.Unmarshal(request); err != nil {
+		return nil, errors.BadRequest("com.HailoOSS.service.config.validate", fmt.Sprintf("%v", err))
+	}
+
+	result, err := domain.ValidateConfig(request.GetId(), request.GetPath())
+	if err != nil {
+		return nil, errors.InternalServerError("com.HailoOSS.service.config.validate", fmt.Sprintf("%v", err))
+	}
+
+	violations := make([]string, len(result.Violations))
+	for i, v := range result.Violations {
+		violations[i] = v.Error()
+	}
+
+	return &validate.Response{
+		IsValid:       proto.Bool(result.IsValid()),
+		PresentSchema: result.Present,
+		AbsentSchema:  result.Absent,
+		Error:         violations,
+	}, nil
+}
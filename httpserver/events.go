@@ -0,0 +1,180 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/cihub/seelog"
+
+	"github.com/HailoOSS/config-service/domain"
+	"github.com/HailoOSS/config-service/handler"
+	"github.com/HailoOSS/platform/errors"
+)
+
+const (
+	// EventsRecheckInterval is how often /events reads the change log regardless, to see
+	// changes written by other instances
+	EventsRecheckInterval = 5 * time.Second
+	// EventsSettle is how far back /events reads the change log each time, so that changes
+	// saved a little after later ones are not missed
+	EventsSettle = 10 * time.Second
+	// EventsKeepAlive is how often /events writes a comment when there are no changes, so
+	// that idle streams are not closed
+	EventsKeepAlive = 30 * time.Second
+)
+
+// eventId identifies a change within the stream, as the unix time it was made and its
+// change ID, so a client can resume from it
+func eventId(cs *domain.ChangeSet) string {
+	return fmt.Sprintf("%d:%s", cs.Timestamp.Unix(), cs.ChangeId)
+}
+
+// parseEventId returns the time and change ID of an event ID
+func parseEventId(id string) (time.Time, string, errors.Error) {
+	parts := strings.SplitN(id, ":", 2)
+	secs, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || len(parts) != 2 {
+		return time.Time{}, "", errors.BadRequest("com.HailoOSS.service.config.http.lasteventid", fmt.Sprintf("Invalid Last-Event-ID: %v", id))
+	}
+	return time.Unix(secs, 0), parts[1], nil
+}
+
+// batchChanges returns the changes within css written by the batch update batchId, and the
+// index of the last of them
+func batchChanges(css []*domain.ChangeSet, batchId string) ([]*domain.ChangeSet, int) {
+	var batch []*domain.ChangeSet
+	last := -1
+	for i, cs := range css {
+		if cs.BatchId == batchId {
+			batch = append(batch, cs)
+			last = i
+		}
+	}
+	return batch, last
+}
+
+// events serves /events?prefix=CITY:, a Server-Sent Events stream of changes to IDs
+// starting with prefix, each carrying the same details as its platform event. Sending
+// the ID of the last event seen as the Last-Event-ID header resumes the stream from the
+// change after it, otherwise the stream starts from now.
+func events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.InternalServerError("com.HailoOSS.service.config.http.events", "Streaming is not supported"))
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	since, after := time.Now(), ""
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		var pfErr errors.Error
+		if since, after, pfErr = parseEventId(last); pfErr != nil {
+			writeError(w, pfErr)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(200)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(EventsKeepAlive)
+	defer keepAlive.Stop()
+
+	// sent holds the changes seen within the settle period, by time made
+	sent := make(map[string]time.Time)
+	latest := since
+	for {
+		// Get the channel before reading, so no change after is missed
+		changed := domain.Changed()
+
+		css, err := domain.ChangesSince(latest.Add(-EventsSettle))
+		if err != nil {
+			log.Warnf("Failed to read change log for event stream: %v", err)
+			return
+		}
+
+		// On resuming, skip up to the last change seen, if it is still to be found
+		skipTo := ""
+		for _, cs := range css {
+			if after != "" && cs.ChangeId == after {
+				skipTo = after
+			}
+		}
+		after = ""
+
+		var fresh []*domain.ChangeSet
+		for _, cs := range css {
+			if _, ok := sent[cs.ChangeId]; ok || cs.Timestamp.Before(since) {
+				continue
+			}
+			sent[cs.ChangeId] = cs.Timestamp
+			if cs.Timestamp.After(latest) {
+				latest = cs.Timestamp
+			}
+			if skipTo != "" {
+				if cs.ChangeId == skipTo {
+					skipTo = ""
+				}
+				continue
+			}
+			// Changes made with noReload were never published
+			if !cs.NoReload {
+				fresh = append(fresh, cs)
+			}
+		}
+
+		wrote := false
+		for i, cs := range fresh {
+			batch := []*domain.ChangeSet{cs}
+			if cs.BatchId != "" {
+				// A batch is published as one event, sent once its last change is reached
+				var last int
+				if batch, last = batchChanges(fresh, cs.BatchId); last != i {
+					continue
+				}
+			}
+			matched := false
+			for _, bcs := range batch {
+				matched = matched || strings.HasPrefix(bcs.Id, prefix)
+			}
+			if !matched {
+				continue
+			}
+			event := handler.ChangeEvent(cs)
+			if cs.BatchId != "" {
+				event = handler.BatchEvent(batch)
+			}
+			b, err := json.Marshal(event)
+			if err != nil {
+				log.Errorf("Error marshaling event for %v: %v", cs.ChangeId, err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", eventId(cs), b)
+			wrote = true
+		}
+		if wrote {
+			flusher.Flush()
+		}
+		for changeId, t := range sent {
+			if t.Before(latest.Add(-EventsSettle)) {
+				delete(sent, changeId)
+			}
+		}
+
+		select {
+		case <-changed:
+		case <-time.After(EventsRecheckInterval):
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
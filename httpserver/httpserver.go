@@ -1,14 +1,17 @@
 package httpserver
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/cihub/seelog"
 
+	"github.com/HailoOSS/config-service/domain"
 	"github.com/HailoOSS/config-service/handler"
 	"github.com/HailoOSS/platform/errors"
 	inst "github.com/HailoOSS/service/instrumentation"
@@ -20,9 +23,54 @@ const (
 	MaxBackoff       = 60 * time.Second
 )
 
+// SecretsToken must be given in the X-Secrets-Token header to reveal secrets over HTTP.
+// If it is empty, secrets are never revealed over HTTP.
+var SecretsToken string
+
+// compileOptions reads the strict, asOf and secrets query parameters
+func compileOptions(r *http.Request) (*domain.CompileOptions, errors.Error) {
+	opts := &domain.CompileOptions{
+		Strict:        r.URL.Query().Get("strict") == "true",
+		RevealSecrets: r.URL.Query().Get("secrets") == "true",
+	}
+	if opts.RevealSecrets {
+		token := r.Header.Get("X-Secrets-Token")
+		if SecretsToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(SecretsToken)) != 1 {
+			return nil, errors.Forbidden("com.HailoOSS.service.config.http.secrets", "Not authorised to reveal secrets")
+		}
+	}
+	if asOf := r.URL.Query().Get("asOf"); asOf != "" {
+		secs, err := strconv.ParseInt(asOf, 10, 64)
+		if err != nil {
+			return nil, errors.BadRequest("com.HailoOSS.service.config.http.asof", fmt.Sprintf("Invalid asOf timestamp: %v", err))
+		}
+		opts.AsOf = time.Unix(secs, 0)
+	}
+	return opts, nil
+}
+
+// writeCompiled writes compiled config as a JSON response, along with the given fields
+func writeCompiled(w http.ResponseWriter, cfg string, response map[string]interface{}) errors.Error {
+	config := map[string]interface{}{}
+	err := json.Unmarshal([]byte(cfg), &config)
+	if err != nil {
+		return errors.InternalServerError("com.HailoOSS.service.config.http.unmarshal", fmt.Sprintf("Failed to unmarshal config, when translating to HTTP response: %v", err))
+	}
+
+	response["config"] = config
+	b, err := json.Marshal(response)
+	if err != nil {
+		return errors.InternalServerError("com.HailoOSS.service.config.http.marshal", fmt.Sprintf("Failed to marshal to HTTP response: %v", err))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(b)
+	return nil
+}
+
 // Server establishes a listener for serving compiled config for HTTP
 func Serve(name, source string, version uint64) {
-	// /compile?ids=foo,bar,baz&path=foo.bar.baz
+	// /compile?ids=foo,bar,baz&path=foo.bar.baz&strict=true&asOf=1403000000&secrets=true
 	http.HandleFunc("/compile", func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		metric := "success"
@@ -33,36 +81,79 @@ func Serve(name, source string, version uint64) {
 		ids := strings.Split(r.URL.Query().Get("ids"), ",")
 		path := strings.Replace(r.URL.Query().Get("path"), ".", "/", -1)
 
-		cfg, hash, pfErr := handler.DoCompile(ids, path)
+		opts, pfErr := compileOptions(r)
 		if pfErr != nil {
 			metric = "error"
 			writeError(w, pfErr)
 			return
 		}
 
-		config := map[string]interface{}{}
-		err := json.Unmarshal([]byte(cfg), &config)
-		if err != nil {
+		cfg, hash, layers, pfErr := handler.DoCompile(ids, path, opts)
+		if pfErr != nil {
 			metric = "error"
-			writeError(w, errors.InternalServerError("com.HailoOSS.service.config.http.unmarshal", fmt.Sprintf("Failed to unmarshal config, when translating to HTTP response: %v", err)))
+			writeError(w, pfErr)
 			return
 		}
 
-		response := map[string]interface{}{
-			"config": config,
+		if pfErr := writeCompiled(w, cfg, map[string]interface{}{
 			"hash":   hash,
+			"layers": layers,
+		}); pfErr != nil {
+			metric = "error"
+			writeError(w, pfErr)
 		}
-		b, err := json.Marshal(response)
-		if err != nil {
+	})
+
+	// /resolve?service=com.HailoOSS.service.foo&region=eu-west-1&env=live&path=foo.bar.baz
+	// Every query parameter other than path, strict, asOf and secrets fills in a variable of the
+	// hierarchy's layers
+	http.HandleFunc("/resolve", func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		metric := "success"
+		defer func() {
+			inst.Timing(1.0, metric+".httpresolve", time.Since(start))
+		}()
+
+		path := strings.Replace(r.URL.Query().Get("path"), ".", "/", -1)
+		vars := make(map[string]string)
+		for name := range r.URL.Query() {
+			switch name {
+			case "path", "strict", "asOf", "secrets":
+			default:
+				vars[name] = r.URL.Query().Get(name)
+			}
+		}
+
+		opts, pfErr := compileOptions(r)
+		if pfErr != nil {
 			metric = "error"
-			writeError(w, errors.InternalServerError("com.HailoOSS.service.config.http.marshal", fmt.Sprintf("Failed to marshal to HTTP response: %v", err)))
+			writeError(w, pfErr)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(200)
-		w.Write(b)
+
+		ids, cfg, hash, layers, pfErr := handler.DoResolve(vars, path, opts)
+		if pfErr != nil {
+			metric = "error"
+			writeError(w, pfErr)
+			return
+		}
+
+		if pfErr := writeCompiled(w, cfg, map[string]interface{}{
+			"hash":   hash,
+			"ids":    ids,
+			"layers": layers,
+		}); pfErr != nil {
+			metric = "error"
+			writeError(w, pfErr)
+		}
 	})
 
+	// /watch?ids=foo,bar,baz&hash=abc&path=foo.bar.baz&strict=true&timeout=30
+	http.HandleFunc("/watch", watch)
+
+	// /events?prefix=CITY:
+	http.HandleFunc("/events", events)
+
 	// root resource
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		response := map[string]interface{}{
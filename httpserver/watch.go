@@ -0,0 +1,108 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HailoOSS/config-service/domain"
+	"github.com/HailoOSS/config-service/handler"
+	"github.com/HailoOSS/platform/errors"
+	inst "github.com/HailoOSS/service/instrumentation"
+)
+
+const (
+	// DefaultWatchTimeout is how long /watch waits for a change, unless told otherwise
+	DefaultWatchTimeout = 30 * time.Second
+	// MaxWatchTimeout is the longest /watch can be told to wait
+	MaxWatchTimeout = 5 * time.Minute
+	// WatchRecheckInterval is how often /watch compiles again regardless, to see changes
+	// written by other instances
+	WatchRecheckInterval = 5 * time.Second
+)
+
+// watchTimeout reads the timeout query parameter, in seconds
+func watchTimeout(r *http.Request) (time.Duration, errors.Error) {
+	param := r.URL.Query().Get("timeout")
+	if param == "" {
+		return DefaultWatchTimeout, nil
+	}
+	secs, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || secs <= 0 {
+		return 0, errors.BadRequest("com.HailoOSS.service.config.http.timeout", fmt.Sprintf("Invalid timeout: %v", param))
+	}
+	if timeout := time.Duration(secs) * time.Second; timeout < MaxWatchTimeout {
+		return timeout, nil
+	}
+	return MaxWatchTimeout, nil
+}
+
+// watch serves /watch?ids=foo,bar,baz&hash=abc&path=foo.bar.baz&strict=true&timeout=30,
+// blocking until the hash of the compiled config differs from the one given, when it is
+// returned as for /compile, or until the timeout passes, when 304 is returned
+func watch(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	metric := "success"
+	defer func() {
+		inst.Timing(1.0, metric+".httpwatch", time.Since(start))
+	}()
+
+	ids := strings.Split(r.URL.Query().Get("ids"), ",")
+	path := strings.Replace(r.URL.Query().Get("path"), ".", "/", -1)
+	known := r.URL.Query().Get("hash")
+
+	opts, pfErr := compileOptions(r)
+	if pfErr != nil {
+		metric = "error"
+		writeError(w, pfErr)
+		return
+	}
+	if !opts.AsOf.IsZero() {
+		metric = "error"
+		writeError(w, errors.BadRequest("com.HailoOSS.service.config.http.asof", "Config as of a past time never changes, so cannot be watched"))
+		return
+	}
+	timeout, pfErr := watchTimeout(r)
+	if pfErr != nil {
+		metric = "error"
+		writeError(w, pfErr)
+		return
+	}
+	deadline := time.After(timeout)
+
+	for {
+		// Get the channel before compiling, so no change after is missed
+		changed := domain.Changed()
+
+		cfg, hash, layers, pfErr := handler.DoCompile(ids, path, opts)
+		if pfErr != nil {
+			metric = "error"
+			writeError(w, pfErr)
+			return
+		}
+		if hash != known {
+			if pfErr := writeCompiled(w, cfg, map[string]interface{}{
+				"hash":   hash,
+				"layers": layers,
+			}); pfErr != nil {
+				metric = "error"
+				writeError(w, pfErr)
+			}
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-time.After(WatchRecheckInterval):
+		case <-r.Context().Done():
+			metric = "gone"
+			return
+		case <-deadline:
+			metric = "timeout"
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+}
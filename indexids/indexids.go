@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	cfg "github.com/HailoOSS/config-service/config"
+	"github.com/HailoOSS/config-service/dao"
+)
+
+// indexids adds every config ID which has not changed since the index of IDs was kept to
+// the index, so that the list endpoint finds it. It only needs running once, and is safe
+// to run again.
+func main() {
+	cfg.Bootstrap()
+	repo := &dao.CassandraRepository{}
+
+	indexed, err := repo.IndexIds()
+	if err != nil {
+		fmt.Printf("Failed to index IDs, after indexing %v: %v\n", indexed, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully indexed %v IDs\n", indexed)
+}
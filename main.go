@@ -1,6 +1,7 @@
 package main
 
 import (
+	"os"
 	"time"
 
 	log "github.com/cihub/seelog"
@@ -17,6 +18,13 @@ import (
 	"github.com/HailoOSS/service/zookeeper"
 )
 
+const (
+	// schedulerInterval is how often we check for scheduled changes which have fallen due
+	schedulerInterval = 10 * time.Second
+	// reaperInterval is how often we check for temporary overrides which have expired
+	reaperInterval = 10 * time.Second
+)
+
 func main() {
 	service.Name = "com.HailoOSS.service.config"
 	service.Description = "Responsible for storing configuration data for applications."
@@ -31,6 +39,12 @@ func main() {
 	// DefaultRepository is the default implementation of the data source
 	domain.DefaultRepository = &dao.CassandraRepository{}
 
+	// Secrets are encrypted with a key from a local file, and revealed over HTTP to those with the token
+	if keyfile := os.Getenv("H2_CONFIG_SERVICE_SECRETS_KEYFILE"); keyfile != "" {
+		domain.DefaultKeyProvider = &domain.KeyfileProvider{Path: keyfile}
+	}
+	httpserver.SecretsToken = os.Getenv("H2_CONFIG_SERVICE_SECRETS_TOKEN")
+
 	// fire off HTTP handler
 	go httpserver.Serve(service.Name, service.Source, service.Version)
 
@@ -43,6 +57,13 @@ func main() {
 		Handler:    handler.Read,
 		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
 	})
+	service.Register(&service.Endpoint{
+		Name:       "list",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.List,
+		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
+	})
 	service.Register(&service.Endpoint{
 		Name:       "compile",
 		Mean:       100,
@@ -50,6 +71,13 @@ func main() {
 		Handler:    handler.Compile,
 		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
 	})
+	service.Register(&service.Endpoint{
+		Name:       "resolve",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Resolve,
+		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
+	})
 	service.Register(&service.Endpoint{
 		Name:       "multicompile",
 		Mean:       300,
@@ -58,6 +86,14 @@ func main() {
 		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
 	})
 
+	service.Register(&service.Endpoint{
+		Name:       "validate",
+		Mean:       200,
+		Upper95:    400,
+		Handler:    handler.Validate,
+		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
+	})
+
 	service.Register(&service.Endpoint{
 		Name:       "update",
 		Mean:       300,
@@ -65,6 +101,20 @@ func main() {
 		Handler:    handler.Update,
 		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
 	})
+	service.Register(&service.Endpoint{
+		Name:       "patch",
+		Mean:       300,
+		Upper95:    500,
+		Handler:    handler.Patch,
+		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "batchupdate",
+		Mean:       500,
+		Upper95:    1000,
+		Handler:    handler.BatchUpdate,
+		Authoriser: service.RoleAuthoriser([]string{"ADMIN"}),
+	})
 	service.Register(&service.Endpoint{
 		Name:       "delete",
 		Mean:       100,
@@ -72,6 +122,90 @@ func main() {
 		Handler:    handler.Delete,
 		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
 	})
+	service.Register(&service.Endpoint{
+		Name:       "deleteid",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.DeleteId,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "undelete",
+		Mean:       300,
+		Upper95:    500,
+		Handler:    handler.Undelete,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "rollback",
+		Mean:       300,
+		Upper95:    500,
+		Handler:    handler.Rollback,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "setsecret",
+		Mean:       300,
+		Upper95:    500,
+		Handler:    handler.SetSecret,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "propose",
+		Mean:       300,
+		Upper95:    500,
+		Handler:    handler.Propose,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "approve",
+		Mean:       300,
+		Upper95:    500,
+		Handler:    handler.Approve,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "reject",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Reject,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "proposals",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Proposals,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "schedule",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Schedule,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "schedules",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Schedules,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "unschedule",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Unschedule,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
+	service.Register(&service.Endpoint{
+		Name:       "overrides",
+		Mean:       100,
+		Upper95:    200,
+		Handler:    handler.Overrides,
+		Authoriser: service.SignInRoleAuthoriser([]string{"ADMIN"}),
+	})
 	service.Register(&service.Endpoint{
 		Name:       "changelog",
 		Mean:       100,
@@ -104,5 +238,10 @@ func main() {
 		log.Criticalf("Failed to connect to ZooKeeper")
 	}
 
+	// apply scheduled changes as they fall due
+	go handler.RunScheduler(schedulerInterval)
+	// revert temporary overrides as they expire
+	go handler.RunReaper(reaperInterval)
+
 	service.BindAndRun()
 }
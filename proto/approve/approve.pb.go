@@ -0,0 +1,75 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/approve/approve.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_approve is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/approve/approve.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_approve
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	ProposalId       *string `protobuf:"bytes,1,req,name=proposalId" json:"proposalId,omitempty"`
+	Message          *string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,3,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetProposalId() string {
+	if m != nil && m.ProposalId != nil {
+		return *m.ProposalId
+	}
+	return ""
+}
+
+func (m *Request) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+type Response struct {
+	ChangeId         *string `protobuf:"bytes,1,req,name=changeId" json:"changeId,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetChangeId() string {
+	if m != nil && m.ChangeId != nil {
+		return *m.ChangeId
+	}
+	return ""
+}
+
+func init() {
+}
@@ -0,0 +1,163 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/batchupdate/batchupdate.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_batchupdate is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/batchupdate/batchupdate.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_batchupdate
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Message          *string           `protobuf:"bytes,1,req,name=message" json:"message,omitempty"`
+	Update           []*Request_Update `protobuf:"bytes,2,rep,name=update" json:"update,omitempty"`
+	NoReload         *bool             `protobuf:"varint,3,opt,name=noReload" json:"noReload,omitempty"`
+	SkipValidation   *bool             `protobuf:"varint,4,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	ValidateCompiled *bool             `protobuf:"varint,5,opt,name=validateCompiled" json:"validateCompiled,omitempty"`
+	BreakGlass       *bool             `protobuf:"varint,6,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	XXX_unrecognized []byte            `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Request) GetUpdate() []*Request_Update {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+func (m *Request) GetNoReload() bool {
+	if m != nil && m.NoReload != nil {
+		return *m.NoReload
+	}
+	return false
+}
+
+func (m *Request) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Request) GetValidateCompiled() bool {
+	if m != nil && m.ValidateCompiled != nil {
+		return *m.ValidateCompiled
+	}
+	return false
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+type Request_Update struct {
+	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Path             *string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	Config           *string `protobuf:"bytes,3,req,name=config" json:"config,omitempty"`
+	Merge            *bool   `protobuf:"varint,4,opt,name=merge" json:"merge,omitempty"`
+	ExpectedHash     *string `protobuf:"bytes,5,opt,name=expectedHash" json:"expectedHash,omitempty"`
+	ExpectedRevision *int64  `protobuf:"varint,6,opt,name=expectedRevision" json:"expectedRevision,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request_Update) Reset()         { *m = Request_Update{} }
+func (m *Request_Update) String() string { return proto.CompactTextString(m) }
+func (*Request_Update) ProtoMessage()    {}
+
+func (m *Request_Update) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request_Update) GetPath() string {
+	if m != nil && m.Path != nil {
+		return *m.Path
+	}
+	return ""
+}
+
+func (m *Request_Update) GetConfig() string {
+	if m != nil && m.Config != nil {
+		return *m.Config
+	}
+	return ""
+}
+
+func (m *Request_Update) GetMerge() bool {
+	if m != nil && m.Merge != nil {
+		return *m.Merge
+	}
+	return false
+}
+
+func (m *Request_Update) GetExpectedHash() string {
+	if m != nil && m.ExpectedHash != nil {
+		return *m.ExpectedHash
+	}
+	return ""
+}
+
+func (m *Request_Update) GetExpectedRevision() int64 {
+	if m != nil && m.ExpectedRevision != nil {
+		return *m.ExpectedRevision
+	}
+	return 0
+}
+
+type Response struct {
+	BatchId          *string  `protobuf:"bytes,1,req,name=batchId" json:"batchId,omitempty"`
+	ChangeId         []string `protobuf:"bytes,2,rep,name=changeId" json:"changeId,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetBatchId() string {
+	if m != nil && m.BatchId != nil {
+		return *m.BatchId
+	}
+	return ""
+}
+
+func (m *Response) GetChangeId() []string {
+	if m != nil {
+		return m.ChangeId
+	}
+	return nil
+}
+
+func init() {
+}
@@ -11,6 +11,7 @@ It is generated from these files:
 It has these top-level messages:
 	ChangeMeta
 	Change
+	Layer
 */
 package com_HailoOSS_service_config
 
@@ -29,6 +30,7 @@ type ChangeMeta struct {
 	AuthMechanism    *string `protobuf:"bytes,2,req,name=authMechanism" json:"authMechanism,omitempty"`
 	UserId           *string `protobuf:"bytes,3,req,name=userId" json:"userId,omitempty"`
 	Message          *string `protobuf:"bytes,4,req,name=message" json:"message,omitempty"`
+	Revision         *int64  `protobuf:"varint,6,opt,name=revision" json:"revision,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -71,6 +73,13 @@ func (m *ChangeMeta) GetMessage() string {
 	return ""
 }
 
+func (m *ChangeMeta) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
 type Change struct {
 	ChangeId         *string `protobuf:"bytes,1,req,name=changeId" json:"changeId,omitempty"`
 	Id               *string `protobuf:"bytes,2,req,name=id" json:"id,omitempty"`
@@ -81,6 +90,18 @@ type Change struct {
 	Config           *string `protobuf:"bytes,7,req,name=config" json:"config,omitempty"`
 	Path             *string `protobuf:"bytes,8,opt,name=path" json:"path,omitempty"`
 	OldConfig        *string `protobuf:"bytes,9,opt,name=oldConfig" json:"oldConfig,omitempty"`
+	SkipValidation   *bool   `protobuf:"varint,10,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	Revision         *int64  `protobuf:"varint,11,opt,name=revision" json:"revision,omitempty"`
+	RolledBackTo     *int64  `protobuf:"varint,12,opt,name=rolledBackTo" json:"rolledBackTo,omitempty"`
+	Patch            *string `protobuf:"bytes,13,opt,name=patch" json:"patch,omitempty"`
+	MergePatch       *string `protobuf:"bytes,14,opt,name=mergePatch" json:"mergePatch,omitempty"`
+	NewConfig        *string `protobuf:"bytes,15,opt,name=newConfig" json:"newConfig,omitempty"`
+	BatchId          *string `protobuf:"bytes,16,opt,name=batchId" json:"batchId,omitempty"`
+	Deleted          *bool   `protobuf:"varint,17,opt,name=deleted" json:"deleted,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,18,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	ScheduleId       *string `protobuf:"bytes,19,opt,name=scheduleId" json:"scheduleId,omitempty"`
+	ExpiresAt        *int64  `protobuf:"varint,20,opt,name=expiresAt" json:"expiresAt,omitempty"`
+	Reverts          *string `protobuf:"bytes,21,opt,name=reverts" json:"reverts,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -151,5 +172,121 @@ func (m *Change) GetOldConfig() string {
 	return ""
 }
 
+func (m *Change) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Change) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
+func (m *Change) GetRolledBackTo() int64 {
+	if m != nil && m.RolledBackTo != nil {
+		return *m.RolledBackTo
+	}
+	return 0
+}
+
+func (m *Change) GetPatch() string {
+	if m != nil && m.Patch != nil {
+		return *m.Patch
+	}
+	return ""
+}
+
+func (m *Change) GetMergePatch() string {
+	if m != nil && m.MergePatch != nil {
+		return *m.MergePatch
+	}
+	return ""
+}
+
+func (m *Change) GetNewConfig() string {
+	if m != nil && m.NewConfig != nil {
+		return *m.NewConfig
+	}
+	return ""
+}
+
+func (m *Change) GetBatchId() string {
+	if m != nil && m.BatchId != nil {
+		return *m.BatchId
+	}
+	return ""
+}
+
+func (m *Change) GetDeleted() bool {
+	if m != nil && m.Deleted != nil {
+		return *m.Deleted
+	}
+	return false
+}
+
+func (m *Change) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+func (m *Change) GetScheduleId() string {
+	if m != nil && m.ScheduleId != nil {
+		return *m.ScheduleId
+	}
+	return ""
+}
+
+func (m *Change) GetExpiresAt() int64 {
+	if m != nil && m.ExpiresAt != nil {
+		return *m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Change) GetReverts() string {
+	if m != nil && m.Reverts != nil {
+		return *m.Reverts
+	}
+	return ""
+}
+
+type Layer struct {
+	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	ChangeId         *string `protobuf:"bytes,2,req,name=changeId" json:"changeId,omitempty"`
+	Revision         *int64  `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Layer) Reset()         { *m = Layer{} }
+func (m *Layer) String() string { return proto.CompactTextString(m) }
+func (*Layer) ProtoMessage()    {}
+
+func (m *Layer) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Layer) GetChangeId() string {
+	if m != nil && m.ChangeId != nil {
+		return *m.ChangeId
+	}
+	return ""
+}
+
+func (m *Layer) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
 func init() {
 }
@@ -17,6 +17,7 @@ package com_HailoOSS_service_config_compile
 import proto "github.com/HailoOSS/protobuf/proto"
 import json "encoding/json"
 import math "math"
+import com_HailoOSS_service_config "github.com/HailoOSS/config-service/proto"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -26,6 +27,9 @@ var _ = math.Inf
 type Request struct {
 	Id               []string `protobuf:"bytes,1,rep,name=id" json:"id,omitempty"`
 	Path             *string  `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	Strict           *bool    `protobuf:"varint,3,opt,name=strict" json:"strict,omitempty"`
+	AsOf             *int64   `protobuf:"varint,4,opt,name=asOf" json:"asOf,omitempty"`
+	RevealSecrets    *bool    `protobuf:"varint,5,opt,name=revealSecrets" json:"revealSecrets,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -47,10 +51,32 @@ func (m *Request) GetPath() string {
 	return ""
 }
 
+func (m *Request) GetStrict() bool {
+	if m != nil && m.Strict != nil {
+		return *m.Strict
+	}
+	return false
+}
+
+func (m *Request) GetAsOf() int64 {
+	if m != nil && m.AsOf != nil {
+		return *m.AsOf
+	}
+	return 0
+}
+
+func (m *Request) GetRevealSecrets() bool {
+	if m != nil && m.RevealSecrets != nil {
+		return *m.RevealSecrets
+	}
+	return false
+}
+
 type Response struct {
-	Config           *string `protobuf:"bytes,1,req,name=config" json:"config,omitempty"`
-	Hash             *string `protobuf:"bytes,2,req,name=hash" json:"hash,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Config           *string                              `protobuf:"bytes,1,req,name=config" json:"config,omitempty"`
+	Hash             *string                              `protobuf:"bytes,2,req,name=hash" json:"hash,omitempty"`
+	Layer            []*com_HailoOSS_service_config.Layer `protobuf:"bytes,3,rep,name=layer" json:"layer,omitempty"`
+	XXX_unrecognized []byte                               `json:"-"`
 }
 
 func (m *Response) Reset()         { *m = Response{} }
@@ -71,5 +97,12 @@ func (m *Response) GetHash() string {
 	return ""
 }
 
+func (m *Response) GetLayer() []*com_HailoOSS_service_config.Layer {
+	if m != nil {
+		return m.Layer
+	}
+	return nil
+}
+
 func init() {
 }
@@ -27,6 +27,11 @@ type Request struct {
 	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
 	Path             *string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
 	Message          *string `protobuf:"bytes,3,req,name=message" json:"message,omitempty"`
+	SkipValidation   *bool   `protobuf:"varint,4,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	ValidateCompiled *bool   `protobuf:"varint,5,opt,name=validateCompiled" json:"validateCompiled,omitempty"`
+	ExpectedHash     *string `protobuf:"bytes,6,opt,name=expectedHash" json:"expectedHash,omitempty"`
+	ExpectedRevision *int64  `protobuf:"varint,7,opt,name=expectedRevision" json:"expectedRevision,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,8,opt,name=breakGlass" json:"breakGlass,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -55,6 +60,41 @@ func (m *Request) GetMessage() string {
 	return ""
 }
 
+func (m *Request) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Request) GetValidateCompiled() bool {
+	if m != nil && m.ValidateCompiled != nil {
+		return *m.ValidateCompiled
+	}
+	return false
+}
+
+func (m *Request) GetExpectedHash() string {
+	if m != nil && m.ExpectedHash != nil {
+		return *m.ExpectedHash
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedRevision() int64 {
+	if m != nil && m.ExpectedRevision != nil {
+		return *m.ExpectedRevision
+	}
+	return 0
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
 type Response struct {
 	XXX_unrecognized []byte `json:"-"`
 }
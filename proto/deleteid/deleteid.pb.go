@@ -0,0 +1,91 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/deleteid/deleteid.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_deleteid is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/deleteid/deleteid.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_deleteid
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Message          *string `protobuf:"bytes,2,req,name=message" json:"message,omitempty"`
+	ExpectedHash     *string `protobuf:"bytes,3,opt,name=expectedHash" json:"expectedHash,omitempty"`
+	ExpectedRevision *int64  `protobuf:"varint,4,opt,name=expectedRevision" json:"expectedRevision,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,5,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedHash() string {
+	if m != nil && m.ExpectedHash != nil {
+		return *m.ExpectedHash
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedRevision() int64 {
+	if m != nil && m.ExpectedRevision != nil {
+		return *m.ExpectedRevision
+	}
+	return 0
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+type Response struct {
+	Revision         *int64 `protobuf:"varint,1,req,name=revision" json:"revision,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
+func init() {
+}
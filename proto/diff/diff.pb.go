@@ -24,10 +24,11 @@ var _ = &json.SyntaxError{}
 var _ = math.Inf
 
 type Request struct {
-	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
-	Path             *string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
-	Config           *string `protobuf:"bytes,3,req,name=config" json:"config,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Id               *string  `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Path             *string  `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	Config           *string  `protobuf:"bytes,3,req,name=config" json:"config,omitempty"`
+	CompileId        []string `protobuf:"bytes,4,rep,name=compileId" json:"compileId,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
@@ -55,6 +56,13 @@ func (m *Request) GetConfig() string {
 	return ""
 }
 
+func (m *Request) GetCompileId() []string {
+	if m != nil {
+		return m.CompileId
+	}
+	return nil
+}
+
 type Response struct {
 	Diff             *string `protobuf:"bytes,1,opt,name=diff" json:"diff,omitempty"`
 	Patch            *string `protobuf:"bytes,2,opt,name=patch" json:"patch,omitempty"`
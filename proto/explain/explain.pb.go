@@ -17,6 +17,7 @@ package com_HailoOSS_service_config_explain
 import proto "github.com/HailoOSS/protobuf/proto"
 import json "encoding/json"
 import math "math"
+import com_HailoOSS_service_config "github.com/HailoOSS/config-service/proto"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -26,6 +27,7 @@ var _ = math.Inf
 type Request struct {
 	Id               []string `protobuf:"bytes,1,rep,name=id" json:"id,omitempty"`
 	Path             *string  `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	AsOf             *int64   `protobuf:"varint,3,opt,name=asOf" json:"asOf,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -47,9 +49,17 @@ func (m *Request) GetPath() string {
 	return ""
 }
 
+func (m *Request) GetAsOf() int64 {
+	if m != nil && m.AsOf != nil {
+		return *m.AsOf
+	}
+	return 0
+}
+
 type Response struct {
-	Config           *string `protobuf:"bytes,1,req,name=config" json:"config,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Config           *string                              `protobuf:"bytes,1,req,name=config" json:"config,omitempty"`
+	Layer            []*com_HailoOSS_service_config.Layer `protobuf:"bytes,2,rep,name=layer" json:"layer,omitempty"`
+	XXX_unrecognized []byte                               `json:"-"`
 }
 
 func (m *Response) Reset()         { *m = Response{} }
@@ -63,5 +73,12 @@ func (m *Response) GetConfig() string {
 	return ""
 }
 
+func (m *Response) GetLayer() []*com_HailoOSS_service_config.Layer {
+	if m != nil {
+		return m.Layer
+	}
+	return nil
+}
+
 func init() {
 }
@@ -0,0 +1,108 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/list/list.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_list is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/list/list.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_list
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+import com_HailoOSS_service_config "github.com/HailoOSS/config-service/proto"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Prefix           *string `protobuf:"bytes,1,opt,name=prefix" json:"prefix,omitempty"`
+	Cursor           *string `protobuf:"bytes,2,opt,name=cursor" json:"cursor,omitempty"`
+	Count            *int32  `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetPrefix() string {
+	if m != nil && m.Prefix != nil {
+		return *m.Prefix
+	}
+	return ""
+}
+
+func (m *Request) GetCursor() string {
+	if m != nil && m.Cursor != nil {
+		return *m.Cursor
+	}
+	return ""
+}
+
+func (m *Request) GetCount() int32 {
+	if m != nil && m.Count != nil {
+		return *m.Count
+	}
+	return 0
+}
+
+type Response struct {
+	Ids              []*Response_Id `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+	Cursor           *string        `protobuf:"bytes,2,opt,name=cursor" json:"cursor,omitempty"`
+	XXX_unrecognized []byte         `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetIds() []*Response_Id {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *Response) GetCursor() string {
+	if m != nil && m.Cursor != nil {
+		return *m.Cursor
+	}
+	return ""
+}
+
+type Response_Id struct {
+	Id               *string                                 `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Meta             *com_HailoOSS_service_config.ChangeMeta `protobuf:"bytes,2,req,name=meta" json:"meta,omitempty"`
+	XXX_unrecognized []byte                                  `json:"-"`
+}
+
+func (m *Response_Id) Reset()         { *m = Response_Id{} }
+func (m *Response_Id) String() string { return proto.CompactTextString(m) }
+func (*Response_Id) ProtoMessage()    {}
+
+func (m *Response_Id) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Response_Id) GetMeta() *com_HailoOSS_service_config.ChangeMeta {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func init() {
+}
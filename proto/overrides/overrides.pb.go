@@ -0,0 +1,179 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/overrides/overrides.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_overrides is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/overrides/overrides.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_overrides
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Active           *bool   `protobuf:"varint,2,opt,name=active" json:"active,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetActive() bool {
+	if m != nil && m.Active != nil {
+		return *m.Active
+	}
+	return false
+}
+
+type Response struct {
+	Overrides        []*Response_Override `protobuf:"bytes,1,rep,name=overrides" json:"overrides,omitempty"`
+	XXX_unrecognized []byte               `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetOverrides() []*Response_Override {
+	if m != nil {
+		return m.Overrides
+	}
+	return nil
+}
+
+type Response_Override struct {
+	OverrideId       *string `protobuf:"bytes,1,req,name=overrideId" json:"overrideId,omitempty"`
+	Id               *string `protobuf:"bytes,2,req,name=id" json:"id,omitempty"`
+	Path             *string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+	Config           *string `protobuf:"bytes,4,opt,name=config" json:"config,omitempty"`
+	OldConfig        *string `protobuf:"bytes,5,opt,name=oldConfig" json:"oldConfig,omitempty"`
+	ExpiresAt        *int64  `protobuf:"varint,6,req,name=expiresAt" json:"expiresAt,omitempty"`
+	AuthMechanism    *string `protobuf:"bytes,7,opt,name=authMechanism" json:"authMechanism,omitempty"`
+	UserId           *string `protobuf:"bytes,8,opt,name=userId" json:"userId,omitempty"`
+	Message          *string `protobuf:"bytes,9,opt,name=message" json:"message,omitempty"`
+	Timestamp        *int64  `protobuf:"varint,10,opt,name=timestamp" json:"timestamp,omitempty"`
+	Status           *string `protobuf:"bytes,11,req,name=status" json:"status,omitempty"`
+	RevertChangeId   *string `protobuf:"bytes,12,opt,name=revertChangeId" json:"revertChangeId,omitempty"`
+	Error            *string `protobuf:"bytes,13,opt,name=error" json:"error,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Response_Override) Reset()         { *m = Response_Override{} }
+func (m *Response_Override) String() string { return proto.CompactTextString(m) }
+func (*Response_Override) ProtoMessage()    {}
+
+func (m *Response_Override) GetOverrideId() string {
+	if m != nil && m.OverrideId != nil {
+		return *m.OverrideId
+	}
+	return ""
+}
+
+func (m *Response_Override) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Response_Override) GetPath() string {
+	if m != nil && m.Path != nil {
+		return *m.Path
+	}
+	return ""
+}
+
+func (m *Response_Override) GetConfig() string {
+	if m != nil && m.Config != nil {
+		return *m.Config
+	}
+	return ""
+}
+
+func (m *Response_Override) GetOldConfig() string {
+	if m != nil && m.OldConfig != nil {
+		return *m.OldConfig
+	}
+	return ""
+}
+
+func (m *Response_Override) GetExpiresAt() int64 {
+	if m != nil && m.ExpiresAt != nil {
+		return *m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Response_Override) GetAuthMechanism() string {
+	if m != nil && m.AuthMechanism != nil {
+		return *m.AuthMechanism
+	}
+	return ""
+}
+
+func (m *Response_Override) GetUserId() string {
+	if m != nil && m.UserId != nil {
+		return *m.UserId
+	}
+	return ""
+}
+
+func (m *Response_Override) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Response_Override) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *Response_Override) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *Response_Override) GetRevertChangeId() string {
+	if m != nil && m.RevertChangeId != nil {
+		return *m.RevertChangeId
+	}
+	return ""
+}
+
+func (m *Response_Override) GetError() string {
+	if m != nil && m.Error != nil {
+		return *m.Error
+	}
+	return ""
+}
+
+func init() {
+}
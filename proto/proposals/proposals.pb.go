@@ -0,0 +1,156 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/proposals/proposals.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_proposals is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/proposals/proposals.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_proposals
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+import com_HailoOSS_service_config "github.com/HailoOSS/config-service/proto"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	ProposalId       *string `protobuf:"bytes,2,opt,name=proposalId" json:"proposalId,omitempty"`
+	Pending          *bool   `protobuf:"varint,3,opt,name=pending" json:"pending,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetProposalId() string {
+	if m != nil && m.ProposalId != nil {
+		return *m.ProposalId
+	}
+	return ""
+}
+
+func (m *Request) GetPending() bool {
+	if m != nil && m.Pending != nil {
+		return *m.Pending
+	}
+	return false
+}
+
+type Response struct {
+	Proposals        []*Response_Proposal `protobuf:"bytes,1,rep,name=proposals" json:"proposals,omitempty"`
+	XXX_unrecognized []byte               `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetProposals() []*Response_Proposal {
+	if m != nil {
+		return m.Proposals
+	}
+	return nil
+}
+
+type Response_Proposal struct {
+	ProposalId          *string                             `protobuf:"bytes,1,req,name=proposalId" json:"proposalId,omitempty"`
+	Status              *string                             `protobuf:"bytes,2,req,name=status" json:"status,omitempty"`
+	Change              *com_HailoOSS_service_config.Change `protobuf:"bytes,3,req,name=change" json:"change,omitempty"`
+	Diff                *string                             `protobuf:"bytes,4,opt,name=diff" json:"diff,omitempty"`
+	Patch               *string                             `protobuf:"bytes,5,opt,name=patch" json:"patch,omitempty"`
+	ReviewAuthMechanism *string                             `protobuf:"bytes,6,opt,name=reviewAuthMechanism" json:"reviewAuthMechanism,omitempty"`
+	ReviewUserId        *string                             `protobuf:"bytes,7,opt,name=reviewUserId" json:"reviewUserId,omitempty"`
+	ReviewMessage       *string                             `protobuf:"bytes,8,opt,name=reviewMessage" json:"reviewMessage,omitempty"`
+	ReviewTimestamp     *int64                              `protobuf:"varint,9,opt,name=reviewTimestamp" json:"reviewTimestamp,omitempty"`
+	XXX_unrecognized    []byte                              `json:"-"`
+}
+
+func (m *Response_Proposal) Reset()         { *m = Response_Proposal{} }
+func (m *Response_Proposal) String() string { return proto.CompactTextString(m) }
+func (*Response_Proposal) ProtoMessage()    {}
+
+func (m *Response_Proposal) GetProposalId() string {
+	if m != nil && m.ProposalId != nil {
+		return *m.ProposalId
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetChange() *com_HailoOSS_service_config.Change {
+	if m != nil {
+		return m.Change
+	}
+	return nil
+}
+
+func (m *Response_Proposal) GetDiff() string {
+	if m != nil && m.Diff != nil {
+		return *m.Diff
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetPatch() string {
+	if m != nil && m.Patch != nil {
+		return *m.Patch
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetReviewAuthMechanism() string {
+	if m != nil && m.ReviewAuthMechanism != nil {
+		return *m.ReviewAuthMechanism
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetReviewUserId() string {
+	if m != nil && m.ReviewUserId != nil {
+		return *m.ReviewUserId
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetReviewMessage() string {
+	if m != nil && m.ReviewMessage != nil {
+		return *m.ReviewMessage
+	}
+	return ""
+}
+
+func (m *Response_Proposal) GetReviewTimestamp() int64 {
+	if m != nil && m.ReviewTimestamp != nil {
+		return *m.ReviewTimestamp
+	}
+	return 0
+}
+
+func init() {
+}
@@ -0,0 +1,139 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/propose/propose.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_propose is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/propose/propose.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_propose
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Path             *string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	Config           *string `protobuf:"bytes,3,req,name=config" json:"config,omitempty"`
+	Message          *string `protobuf:"bytes,4,req,name=message" json:"message,omitempty"`
+	SkipValidation   *bool   `protobuf:"varint,5,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	ValidateCompiled *bool   `protobuf:"varint,6,opt,name=validateCompiled" json:"validateCompiled,omitempty"`
+	Merge            *bool   `protobuf:"varint,7,opt,name=merge" json:"merge,omitempty"`
+	ExpectedHash     *string `protobuf:"bytes,8,opt,name=expectedHash" json:"expectedHash,omitempty"`
+	ExpectedRevision *int64  `protobuf:"varint,9,opt,name=expectedRevision" json:"expectedRevision,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetPath() string {
+	if m != nil && m.Path != nil {
+		return *m.Path
+	}
+	return ""
+}
+
+func (m *Request) GetConfig() string {
+	if m != nil && m.Config != nil {
+		return *m.Config
+	}
+	return ""
+}
+
+func (m *Request) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Request) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Request) GetValidateCompiled() bool {
+	if m != nil && m.ValidateCompiled != nil {
+		return *m.ValidateCompiled
+	}
+	return false
+}
+
+func (m *Request) GetMerge() bool {
+	if m != nil && m.Merge != nil {
+		return *m.Merge
+	}
+	return false
+}
+
+func (m *Request) GetExpectedHash() string {
+	if m != nil && m.ExpectedHash != nil {
+		return *m.ExpectedHash
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedRevision() int64 {
+	if m != nil && m.ExpectedRevision != nil {
+		return *m.ExpectedRevision
+	}
+	return 0
+}
+
+type Response struct {
+	ProposalId       *string `protobuf:"bytes,1,req,name=proposalId" json:"proposalId,omitempty"`
+	Diff             *string `protobuf:"bytes,2,opt,name=diff" json:"diff,omitempty"`
+	Patch            *string `protobuf:"bytes,3,opt,name=patch" json:"patch,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetProposalId() string {
+	if m != nil && m.ProposalId != nil {
+		return *m.ProposalId
+	}
+	return ""
+}
+
+func (m *Response) GetDiff() string {
+	if m != nil && m.Diff != nil {
+		return *m.Diff
+	}
+	return ""
+}
+
+func (m *Response) GetPatch() string {
+	if m != nil && m.Patch != nil {
+		return *m.Patch
+	}
+	return ""
+}
+
+func init() {
+}
@@ -27,6 +27,8 @@ var _ = math.Inf
 type Request struct {
 	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
 	Path             *string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	Revision         *int64  `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
+	Timestamp        *int64  `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -48,6 +50,20 @@ func (m *Request) GetPath() string {
 	return ""
 }
 
+func (m *Request) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
+func (m *Request) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
 type Response struct {
 	Config           *string                                 `protobuf:"bytes,1,req,name=config" json:"config,omitempty"`
 	Hash             *string                                 `protobuf:"bytes,2,req,name=hash" json:"hash,omitempty"`
@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/resolve/resolve.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_resolve is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/resolve/resolve.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_resolve
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+import com_HailoOSS_service_config "github.com/HailoOSS/config-service/proto"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Service          *string             `protobuf:"bytes,1,opt,name=service" json:"service,omitempty"`
+	Region           *string             `protobuf:"bytes,2,opt,name=region" json:"region,omitempty"`
+	Env              *string             `protobuf:"bytes,3,opt,name=env" json:"env,omitempty"`
+	Az               *string             `protobuf:"bytes,4,opt,name=az" json:"az,omitempty"`
+	Variable         []*Request_Variable `protobuf:"bytes,5,rep,name=variable" json:"variable,omitempty"`
+	Path             *string             `protobuf:"bytes,6,opt,name=path" json:"path,omitempty"`
+	Strict           *bool               `protobuf:"varint,7,opt,name=strict" json:"strict,omitempty"`
+	RevealSecrets    *bool               `protobuf:"varint,8,opt,name=revealSecrets" json:"revealSecrets,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetService() string {
+	if m != nil && m.Service != nil {
+		return *m.Service
+	}
+	return ""
+}
+
+func (m *Request) GetRegion() string {
+	if m != nil && m.Region != nil {
+		return *m.Region
+	}
+	return ""
+}
+
+func (m *Request) GetEnv() string {
+	if m != nil && m.Env != nil {
+		return *m.Env
+	}
+	return ""
+}
+
+func (m *Request) GetAz() string {
+	if m != nil && m.Az != nil {
+		return *m.Az
+	}
+	return ""
+}
+
+func (m *Request) GetVariable() []*Request_Variable {
+	if m != nil {
+		return m.Variable
+	}
+	return nil
+}
+
+func (m *Request) GetPath() string {
+	if m != nil && m.Path != nil {
+		return *m.Path
+	}
+	return ""
+}
+
+func (m *Request) GetStrict() bool {
+	if m != nil && m.Strict != nil {
+		return *m.Strict
+	}
+	return false
+}
+
+func (m *Request) GetRevealSecrets() bool {
+	if m != nil && m.RevealSecrets != nil {
+		return *m.RevealSecrets
+	}
+	return false
+}
+
+type Request_Variable struct {
+	Name             *string `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Value            *string `protobuf:"bytes,2,req,name=value" json:"value,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request_Variable) Reset()         { *m = Request_Variable{} }
+func (m *Request_Variable) String() string { return proto.CompactTextString(m) }
+func (*Request_Variable) ProtoMessage()    {}
+
+func (m *Request_Variable) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *Request_Variable) GetValue() string {
+	if m != nil && m.Value != nil {
+		return *m.Value
+	}
+	return ""
+}
+
+type Response struct {
+	Config           *string                              `protobuf:"bytes,1,req,name=config" json:"config,omitempty"`
+	Hash             *string                              `protobuf:"bytes,2,req,name=hash" json:"hash,omitempty"`
+	Id               []string                             `protobuf:"bytes,3,rep,name=id" json:"id,omitempty"`
+	Layer            []*com_HailoOSS_service_config.Layer `protobuf:"bytes,4,rep,name=layer" json:"layer,omitempty"`
+	XXX_unrecognized []byte                               `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetConfig() string {
+	if m != nil && m.Config != nil {
+		return *m.Config
+	}
+	return ""
+}
+
+func (m *Response) GetHash() string {
+	if m != nil && m.Hash != nil {
+		return *m.Hash
+	}
+	return ""
+}
+
+func (m *Response) GetId() []string {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Response) GetLayer() []*com_HailoOSS_service_config.Layer {
+	if m != nil {
+		return m.Layer
+	}
+	return nil
+}
+
+func init() {
+}
@@ -0,0 +1,115 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/rollback/rollback.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_rollback is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/rollback/rollback.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_rollback
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Message          *string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	Revision         *int64  `protobuf:"varint,3,opt,name=revision" json:"revision,omitempty"`
+	ChangeId         *string `protobuf:"bytes,4,opt,name=changeId" json:"changeId,omitempty"`
+	Undo             *int64  `protobuf:"varint,5,opt,name=undo" json:"undo,omitempty"`
+	SkipValidation   *bool   `protobuf:"varint,6,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,7,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Request) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
+func (m *Request) GetChangeId() string {
+	if m != nil && m.ChangeId != nil {
+		return *m.ChangeId
+	}
+	return ""
+}
+
+func (m *Request) GetUndo() int64 {
+	if m != nil && m.Undo != nil {
+		return *m.Undo
+	}
+	return 0
+}
+
+func (m *Request) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+type Response struct {
+	Revision         *int64 `protobuf:"varint,1,req,name=revision" json:"revision,omitempty"`
+	RolledBackTo     *int64 `protobuf:"varint,2,req,name=rolledBackTo" json:"rolledBackTo,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetRevision() int64 {
+	if m != nil && m.Revision != nil {
+		return *m.Revision
+	}
+	return 0
+}
+
+func (m *Response) GetRolledBackTo() int64 {
+	if m != nil && m.RolledBackTo != nil {
+		return *m.RolledBackTo
+	}
+	return 0
+}
+
+func init() {
+}
@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/schedule/schedule.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_schedule is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/schedule/schedule.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_schedule
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`
+	Path             *string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
+	Config           *string `protobuf:"bytes,3,opt,name=config" json:"config,omitempty"`
+	Patch            *string `protobuf:"bytes,4,opt,name=patch" json:"patch,omitempty"`
+	Message          *string `protobuf:"bytes,5,req,name=message" json:"message,omitempty"`
+	ApplyAt          *int64  `protobuf:"varint,6,req,name=applyAt" json:"applyAt,omitempty"`
+	Merge            *bool   `protobuf:"varint,7,opt,name=merge" json:"merge,omitempty"`
+	SkipValidation   *bool   `protobuf:"varint,8,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	ValidateCompiled *bool   `protobuf:"varint,9,opt,name=validateCompiled" json:"validateCompiled,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,10,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetPath() string {
+	if m != nil && m.Path != nil {
+		return *m.Path
+	}
+	return ""
+}
+
+func (m *Request) GetConfig() string {
+	if m != nil && m.Config != nil {
+		return *m.Config
+	}
+	return ""
+}
+
+func (m *Request) GetPatch() string {
+	if m != nil && m.Patch != nil {
+		return *m.Patch
+	}
+	return ""
+}
+
+func (m *Request) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Request) GetApplyAt() int64 {
+	if m != nil && m.ApplyAt != nil {
+		return *m.ApplyAt
+	}
+	return 0
+}
+
+func (m *Request) GetMerge() bool {
+	if m != nil && m.Merge != nil {
+		return *m.Merge
+	}
+	return false
+}
+
+func (m *Request) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Request) GetValidateCompiled() bool {
+	if m != nil && m.ValidateCompiled != nil {
+		return *m.ValidateCompiled
+	}
+	return false
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+type Response struct {
+	ScheduleId       *string `protobuf:"bytes,1,req,name=scheduleId" json:"scheduleId,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetScheduleId() string {
+	if m != nil && m.ScheduleId != nil {
+		return *m.ScheduleId
+	}
+	return ""
+}
+
+func init() {
+}
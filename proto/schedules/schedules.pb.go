@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go.
+// source: github.com/HailoOSS/config-service/proto/schedules/schedules.proto
+// DO NOT EDIT!
+
+/*
+Package com_HailoOSS_service_config_schedules is a generated protocol buffer package.
+
+It is generated from these files:
+	github.com/HailoOSS/config-service/proto/schedules/schedules.proto
+
+It has these top-level messages:
+	Request
+	Response
+*/
+package com_HailoOSS_service_config_schedules
+
+import proto "github.com/HailoOSS/protobuf/proto"
+import json "encoding/json"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = &json.SyntaxError{}
+var _ = math.Inf
+
+type Request struct {
+	Id               *string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	ScheduleId       *string `protobuf:"bytes,2,opt,name=scheduleId" json:"scheduleId,omitempty"`
+	Pending          *bool   `protobuf:"varint,3,opt,name=pending" json:"pending,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+
+func (m *Request) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Request) GetScheduleId() string {
+	if m != nil && m.ScheduleId != nil {
+		return *m.ScheduleId
+	}
+	return ""
+}
+
+func (m *Request) GetPending() bool {
+	if m != nil && m.Pending != nil {
+		return *m.Pending
+	}
+	return false
+}
+
+type Response struct {
+	Schedules        []*Response_Schedule `protobuf:"bytes,1,rep,name=schedules" json:"schedules,omitempty"`
+	XXX_unrecognized []byte               `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+
+func (m *Response) GetSchedules() []*Response_Schedule {
+	if m != nil {
+		return m.Schedules
+	}
+	return nil
+}
+
+type Response_Schedule struct {
+	ScheduleId          *string `protobuf:"bytes,1,req,name=scheduleId" json:"scheduleId,omitempty"`
+	Id                  *string `protobuf:"bytes,2,req,name=id" json:"id,omitempty"`
+	Path                *string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+	Config              *string `protobuf:"bytes,4,opt,name=config" json:"config,omitempty"`
+	Patch               *string `protobuf:"bytes,5,opt,name=patch" json:"patch,omitempty"`
+	Merge               *bool   `protobuf:"varint,6,opt,name=merge" json:"merge,omitempty"`
+	ApplyAt             *int64  `protobuf:"varint,7,req,name=applyAt" json:"applyAt,omitempty"`
+	AuthMechanism       *string `protobuf:"bytes,8,opt,name=authMechanism" json:"authMechanism,omitempty"`
+	UserId              *string `protobuf:"bytes,9,opt,name=userId" json:"userId,omitempty"`
+	Message             *string `protobuf:"bytes,10,opt,name=message" json:"message,omitempty"`
+	Timestamp           *int64  `protobuf:"varint,11,opt,name=timestamp" json:"timestamp,omitempty"`
+	SkipValidation      *bool   `protobuf:"varint,12,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	ValidateCompiled    *bool   `protobuf:"varint,13,opt,name=validateCompiled" json:"validateCompiled,omitempty"`
+	BreakGlass          *bool   `protobuf:"varint,14,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	Status              *string `protobuf:"bytes,15,req,name=status" json:"status,omitempty"`
+	ChangeId            *string `protobuf:"bytes,16,opt,name=changeId" json:"changeId,omitempty"`
+	Error               *string `protobuf:"bytes,17,opt,name=error" json:"error,omitempty"`
+	CancelAuthMechanism *string `protobuf:"bytes,18,opt,name=cancelAuthMechanism" json:"cancelAuthMechanism,omitempty"`
+	CancelUserId        *string `protobuf:"bytes,19,opt,name=cancelUserId" json:"cancelUserId,omitempty"`
+	CancelMessage       *string `protobuf:"bytes,20,opt,name=cancelMessage" json:"cancelMessage,omitempty"`
+	CancelTimestamp     *int64  `protobuf:"varint,21,opt,name=cancelTimestamp" json:"cancelTimestamp,omitempty"`
+	XXX_unrecognized    []byte  `json:"-"`
+}
+
+func (m *Response_Schedule) Reset()         { *m = Response_Schedule{} }
+func (m *Response_Schedule) String() string { return proto.CompactTextString(m) }
+func (*Response_Schedule) ProtoMessage()    {}
+
+func (m *Response_Schedule) GetScheduleId() string {
+	if m != nil && m.ScheduleId != nil {
+		return *m.ScheduleId
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetId() string {
+	if m != nil && m.Id != nil {
+		return *m.Id
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetPath() string {
+	if m != nil && m.Path != nil {
+		return *m.Path
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetConfig() string {
+	if m != nil && m.Config != nil {
+		return *m.Config
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetPatch() string {
+	if m != nil && m.Patch != nil {
+		return *m.Patch
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetMerge() bool {
+	if m != nil && m.Merge != nil {
+		return *m.Merge
+	}
+	return false
+}
+
+func (m *Response_Schedule) GetApplyAt() int64 {
+	if m != nil && m.ApplyAt != nil {
+		return *m.ApplyAt
+	}
+	return 0
+}
+
+func (m *Response_Schedule) GetAuthMechanism() string {
+	if m != nil && m.AuthMechanism != nil {
+		return *m.AuthMechanism
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetUserId() string {
+	if m != nil && m.UserId != nil {
+		return *m.UserId
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetMessage() string {
+	if m != nil && m.Message != nil {
+		return *m.Message
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *Response_Schedule) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Response_Schedule) GetValidateCompiled() bool {
+	if m != nil && m.ValidateCompiled != nil {
+		return *m.ValidateCompiled
+	}
+	return false
+}
+
+func (m *Response_Schedule) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+func (m *Response_Schedule) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetChangeId() string {
+	if m != nil && m.ChangeId != nil {
+		return *m.ChangeId
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetError() string {
+	if m != nil && m.Error != nil {
+		return *m.Error
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetCancelAuthMechanism() string {
+	if m != nil && m.CancelAuthMechanism != nil {
+		return *m.CancelAuthMechanism
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetCancelUserId() string {
+	if m != nil && m.CancelUserId != nil {
+		return *m.CancelUserId
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetCancelMessage() string {
+	if m != nil && m.CancelMessage != nil {
+		return *m.CancelMessage
+	}
+	return ""
+}
+
+func (m *Response_Schedule) GetCancelTimestamp() int64 {
+	if m != nil && m.CancelTimestamp != nil {
+		return *m.CancelTimestamp
+	}
+	return 0
+}
+
+func init() {
+}
@@ -29,6 +29,13 @@ type Request struct {
 	Message          *string `protobuf:"bytes,3,req,name=message" json:"message,omitempty"`
 	Config           *string `protobuf:"bytes,4,req,name=config" json:"config,omitempty"`
 	NoReload         *bool   `protobuf:"varint,5,opt,name=noReload" json:"noReload,omitempty"`
+	SkipValidation   *bool   `protobuf:"varint,6,opt,name=skipValidation" json:"skipValidation,omitempty"`
+	ValidateCompiled *bool   `protobuf:"varint,7,opt,name=validateCompiled" json:"validateCompiled,omitempty"`
+	ExpectedHash     *string `protobuf:"bytes,8,opt,name=expectedHash" json:"expectedHash,omitempty"`
+	ExpectedRevision *int64  `protobuf:"varint,9,opt,name=expectedRevision" json:"expectedRevision,omitempty"`
+	Merge            *bool   `protobuf:"varint,10,opt,name=merge" json:"merge,omitempty"`
+	BreakGlass       *bool   `protobuf:"varint,11,opt,name=breakGlass" json:"breakGlass,omitempty"`
+	Ttl              *int64  `protobuf:"varint,12,opt,name=ttl" json:"ttl,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -71,6 +78,55 @@ func (m *Request) GetNoReload() bool {
 	return false
 }
 
+func (m *Request) GetSkipValidation() bool {
+	if m != nil && m.SkipValidation != nil {
+		return *m.SkipValidation
+	}
+	return false
+}
+
+func (m *Request) GetValidateCompiled() bool {
+	if m != nil && m.ValidateCompiled != nil {
+		return *m.ValidateCompiled
+	}
+	return false
+}
+
+func (m *Request) GetExpectedHash() string {
+	if m != nil && m.ExpectedHash != nil {
+		return *m.ExpectedHash
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedRevision() int64 {
+	if m != nil && m.ExpectedRevision != nil {
+		return *m.ExpectedRevision
+	}
+	return 0
+}
+
+func (m *Request) GetMerge() bool {
+	if m != nil && m.Merge != nil {
+		return *m.Merge
+	}
+	return false
+}
+
+func (m *Request) GetBreakGlass() bool {
+	if m != nil && m.BreakGlass != nil {
+		return *m.BreakGlass
+	}
+	return false
+}
+
+func (m *Request) GetTtl() int64 {
+	if m != nil && m.Ttl != nil {
+		return *m.Ttl
+	}
+	return 0
+}
+
 type Response struct {
 	XXX_unrecognized []byte `json:"-"`
 }